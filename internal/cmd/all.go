@@ -20,12 +20,12 @@ func All() error {
 	}
 
 	fmt.Println()
-	if err := Projects(); err != nil {
+	if err := Projects("messages", ""); err != nil {
 		return err
 	}
 
 	fmt.Println()
-	if err := Sessions(nil); err != nil {
+	if err := Sessions("", Settings.SessionsLimit); err != nil {
 		return err
 	}
 
@@ -102,41 +102,12 @@ func allJSON() error {
 		"peakHours": hours,
 	}
 	if stats.LongestSession.SessionID != "" {
-		summary["longestSession"] = map[string]any{
-			"sessionId": stats.LongestSession.SessionID,
-			"duration":  display.FormatDuration(stats.LongestSession.Duration),
-			"messages":  stats.LongestSession.MessageCount,
-		}
+		summary["longestSession"] = longestSessionJSON(stats.LongestSession)
 	}
 
 	// Projects
-	allProjects, _ := store.LoadAllProjects()
-	sort.Slice(allProjects, func(i, j int) bool {
-		return allProjects[i].MessageCount > allProjects[j].MessageCount
-	})
-	type jsonProject struct {
-		Path       string `json:"path"`
-		Sessions   int    `json:"sessions"`
-		Messages   int    `json:"messages"`
-		LastActive string `json:"lastActive"`
-	}
-	var projectsOut []jsonProject
-	for _, p := range allProjects {
-		name := p.Path
-		if name == "" {
-			name = p.DirName
-		}
-		la := ""
-		if !p.LastActive.IsZero() {
-			la = p.LastActive.Format(time.RFC3339)
-		}
-		projectsOut = append(projectsOut, jsonProject{
-			Path:       name,
-			Sessions:   p.SessionCount,
-			Messages:   p.MessageCount,
-			LastActive: la,
-		})
-	}
+	allProjects, _ := loadProjects("messages", "")
+	projectsOut := toJSONProjects(allProjects)
 
 	// Sessions
 	allSessions, _ := store.ListAllSessions("")
@@ -148,7 +119,7 @@ func allJSON() error {
 		Branch      string `json:"branch,omitempty"`
 		Sidechain   bool   `json:"sidechain,omitempty"`
 	}
-	limit := 20
+	limit := Settings.SessionsLimit
 	if limit > len(allSessions) {
 		limit = len(allSessions)
 	}
@@ -172,8 +143,8 @@ func allJSON() error {
 	}
 	days := stats.DailyModelTokens
 	start := 0
-	if len(days) > 14 {
-		start = len(days) - 14
+	if len(days) > Settings.TokensDays {
+		start = len(days) - Settings.TokensDays
 	}
 	recent := days[start:]
 	var dailyTokens []dailyEntry
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+func archiveFlags(fs *flag.FlagSet) Runner {
+	compress := fs.Bool("compress", false, "Store new copies gzipped")
+	status := fs.Bool("status", false, "Show the archive without copying")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("archive")
+		}
+		return Archive(*compress, *status)
+	}
+}
+
+// Archive copies new and changed session transcripts into ccs's archive so
+// they outlive Claude Code's cleanupPeriodDays
+func Archive(compress, statusOnly bool) error {
+	// Progress is only shown in the terminal view
+	progress := !display.IsJSON() && !display.IsMD()
+	var res *store.ArchiveResult
+	if !statusOnly {
+		if progress {
+			fmt.Println(display.BoldCyan("Archiving sessions..."))
+		}
+		var err error
+		res, err = store.ArchiveSessions(compress, func(done, total int) {
+			if progress {
+				fmt.Printf("\r  Copying... %d/%d sessions", done, total)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("archiving: %w", err)
+		}
+		if progress {
+			fmt.Println()
+		}
+	}
+
+	st, err := store.GetArchiveStatus()
+	if err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+
+	if display.IsJSON() {
+		data := map[string]any{
+			"dir":         st.Dir,
+			"sessions":    st.Sessions,
+			"archiveOnly": st.ArchiveOnly,
+			"bytes":       st.Bytes,
+		}
+		if res != nil {
+			data["added"] = res.Added
+			data["updated"] = res.Updated
+			data["unchanged"] = res.Unchanged
+			data["indexes"] = res.Indexes
+			data["bytesWritten"] = res.Bytes
+		}
+		return OutputJSON(data)
+	}
+
+	if display.IsMD() {
+		MDHeader(2, "Archive")
+		if res != nil {
+			fmt.Printf("- **Added:** %s\n", display.FormatNumber(res.Added))
+			fmt.Printf("- **Updated:** %s\n", display.FormatNumber(res.Updated))
+			fmt.Printf("- **Unchanged:** %s\n", display.FormatNumber(res.Unchanged))
+			fmt.Printf("- **Written:** %s\n", formatBytes(res.Bytes))
+		}
+		fmt.Printf("- **Directory:** %s\n", st.Dir)
+		fmt.Printf("- **Sessions:** %s (%s)\n", display.FormatNumber(st.Sessions), formatBytes(st.Bytes))
+		fmt.Printf("- **Removed by Claude Code:** %s\n", display.FormatNumber(st.ArchiveOnly))
+		if !st.LastArchived.IsZero() {
+			fmt.Printf("- **Last archived:** %s\n", st.LastArchived.Format("2006-01-02 15:04"))
+		}
+		fmt.Println()
+		return nil
+	}
+
+	if res != nil {
+		fmt.Printf("\n  %s %s new, %s updated, %s unchanged (%s written)\n",
+			display.Green("Done."),
+			display.Bold(display.FormatNumber(res.Added)),
+			display.Bold(display.FormatNumber(res.Updated)),
+			display.FormatNumber(res.Unchanged),
+			formatBytes(res.Bytes))
+	}
+	fmt.Printf("\n  Archive   %s\n", st.Dir)
+	fmt.Printf("  Sessions  %s (%s)\n", display.Bold(display.FormatNumber(st.Sessions)), formatBytes(st.Bytes))
+	if st.ArchiveOnly > 0 {
+		fmt.Printf("  Kept      %s sessions already removed by Claude Code\n",
+			display.Bold(display.FormatNumber(st.ArchiveOnly)))
+	}
+	if !st.LastArchived.IsZero() {
+		fmt.Printf("  Updated   %s\n", display.RelativeTime(st.LastArchived))
+	}
+	fmt.Println()
+	return nil
+}
+
+// formatBytes renders a size as B, KB, MB or GB
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
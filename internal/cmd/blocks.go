@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/config"
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+func blocksFlags(fs *flag.FlagSet) Runner {
+	days := fs.Int("days", 7, "Show blocks from the last `N` days")
+	limit := fs.String("limit", "", "Token `LIMIT` per block, e.g. 5M; max = largest past block")
+	active := fs.Bool("active", false, "Only show the current block")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("blocks")
+		}
+		if *days <= 0 {
+			return fmt.Errorf("invalid --days %d", *days)
+		}
+		return Blocks(*days, *limit, *active)
+	}
+}
+
+func Blocks(days int, limitArg string, activeOnly bool) error {
+	events, err := store.LoadUsageEvents(time.Time{})
+	if err != nil {
+		return fmt.Errorf("loading usage: %w", err)
+	}
+	all := store.GroupBlocks(events)
+
+	now := time.Now()
+
+	// Token limit: explicit, or the largest completed block so far
+	limit := 0
+	if limitArg == "max" {
+		for i := range all {
+			if all[i].IsActive(now) {
+				continue
+			}
+			if t := all[i].Total().TotalTokens(); t > limit {
+				limit = t
+			}
+		}
+	} else if limitArg != "" {
+		n, err := config.ParseTokenCount(limitArg)
+		if err != nil {
+			return fmt.Errorf("invalid --limit: %w", err)
+		}
+		limit = n
+	}
+
+	cutoff := now.AddDate(0, 0, -days)
+	var blocks []store.UsageBlock
+	var active *store.UsageBlock
+	for i := range all {
+		if all[i].IsActive(now) {
+			active = &all[i]
+		}
+		if activeOnly && !all[i].IsActive(now) {
+			continue
+		}
+		if all[i].End.After(cutoff) {
+			blocks = append(blocks, all[i])
+		}
+	}
+	// Most recent first
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Start.After(blocks[j].Start)
+	})
+
+	if display.IsJSON() {
+		return blocksJSON(blocks, active, limit, now)
+	}
+	if display.IsMD() {
+		return blocksMD(blocks, active, limit, now)
+	}
+
+	fmt.Println(display.BoldCyan("Usage Blocks"))
+	fmt.Printf("5-hour windows, last %d days\n\n", days)
+
+	if active != nil {
+		p := store.ProjectBlock(active, now)
+		tokens := active.Total().TotalTokens()
+		display.Box("Current Block", func() {
+			fmt.Printf("  Started     %s (%s ago)\n",
+				active.Start.In(Settings.Location()).Format("15:04"),
+				display.FormatDurationFromTime(p.Elapsed))
+			fmt.Printf("  Remaining   %s (until %s)\n",
+				display.Bold(display.FormatDurationFromTime(p.Remaining)),
+				active.End.In(Settings.Location()).Format("15:04"))
+			fmt.Printf("  Tokens      %s\n", display.Bold(display.FormatTokens(tokens)))
+			fmt.Printf("  Cost        %s\n", display.Bold(display.FormatCost(active.Cost())))
+			fmt.Printf("  Burn rate   %s tok/min, %s/h\n",
+				display.FormatTokens(int(p.TokensPerMinute)),
+				display.FormatCost(p.CostPerHour))
+			fmt.Printf("  Projected   %s tokens, %s\n",
+				display.Bold(display.FormatTokens(p.ProjectedTokens)),
+				display.FormatCost(p.ProjectedCost))
+			if limit > 0 {
+				fmt.Printf("  Limit       %s %s\n",
+					display.Bar(tokens, limit, 20),
+					blockLimitStatus(tokens, p.ProjectedTokens, limit))
+			}
+		})
+		fmt.Println()
+	} else {
+		fmt.Printf("%s\n\n", display.Dim("No active block"))
+	}
+
+	if len(blocks) == 0 {
+		fmt.Println(display.Dim("No usage in this range"))
+		return nil
+	}
+
+	display.Box("Blocks", func() {
+		maxTokens := 0
+		for i := range blocks {
+			if t := blocks[i].Total().TotalTokens(); t > maxTokens {
+				maxTokens = t
+			}
+		}
+		if limit > maxTokens {
+			maxTokens = limit
+		}
+		for i := range blocks {
+			b := &blocks[i]
+			tokens := b.Total().TotalTokens()
+			marker := ""
+			if b.IsActive(now) {
+				marker = display.Green(" ● active")
+			} else if limit > 0 && tokens > limit {
+				marker = display.Red(" over limit")
+			}
+			fmt.Printf("  %s  %s %8s  %8s  %s%s\n",
+				b.Start.In(Settings.Location()).Format("2006-01-02 15:04"),
+				display.Bar(tokens, maxTokens, 20),
+				display.FormatTokens(tokens),
+				display.FormatCost(b.Cost()),
+				display.Dim(blockModels(b)),
+				marker)
+		}
+	})
+	fmt.Println()
+
+	return nil
+}
+
+// blockLimitStatus describes current and projected usage against the limit
+func blockLimitStatus(tokens, projected, limit int) string {
+	used := fmt.Sprintf("%s of %s", display.FormatTokens(tokens), display.FormatTokens(limit))
+	switch {
+	case tokens >= limit:
+		return display.Red(used + " — limit reached")
+	case projected > limit:
+		return display.Yellow(used + " — projected to exceed")
+	default:
+		return display.Green(used + " — on track")
+	}
+}
+
+// blockModels lists the short names of models used in a block
+func blockModels(b *store.UsageBlock) string {
+	var names []string
+	for model := range b.Models {
+		names = append(names, display.ModelShort(model))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+type jsonBlock struct {
+	Start     string         `json:"start"`
+	End       string         `json:"end"`
+	LastEntry string         `json:"lastEntry"`
+	Active    bool           `json:"active"`
+	Entries   int            `json:"entries"`
+	Tokens    map[string]int `json:"tokens"`
+	Total     int            `json:"totalTokens"`
+	Cost      float64        `json:"cost"`
+	Models    []string       `json:"models"`
+}
+
+func toJSONBlock(b *store.UsageBlock, now time.Time) jsonBlock {
+	var models []string
+	for model := range b.Models {
+		models = append(models, display.ModelShort(model))
+	}
+	sort.Strings(models)
+	return jsonBlock{
+		Start:     b.Start.Format(time.RFC3339),
+		End:       b.End.Format(time.RFC3339),
+		LastEntry: b.LastAt.Format(time.RFC3339),
+		Active:    b.IsActive(now),
+		Entries:   b.Entries,
+		Tokens:    usageMap(b.Total()),
+		Total:     b.Total().TotalTokens(),
+		Cost:      b.Cost(),
+		Models:    models,
+	}
+}
+
+func blocksJSON(blocks []store.UsageBlock, active *store.UsageBlock, limit int, now time.Time) error {
+	out := make([]jsonBlock, 0, len(blocks))
+	for i := range blocks {
+		out = append(out, toJSONBlock(&blocks[i], now))
+	}
+
+	data := map[string]any{
+		"blocks": out,
+	}
+	if limit > 0 {
+		data["limit"] = limit
+	}
+	if active != nil {
+		p := store.ProjectBlock(active, now)
+		cur := map[string]any{
+			"block":            toJSONBlock(active, now),
+			"elapsedMinutes":   int(p.Elapsed.Minutes()),
+			"remainingMinutes": int(p.Remaining.Minutes()),
+			"tokensPerMinute":  p.TokensPerMinute,
+			"costPerHour":      p.CostPerHour,
+			"projectedTokens":  p.ProjectedTokens,
+			"projectedCost":    p.ProjectedCost,
+		}
+		if limit > 0 {
+			cur["projectedToExceed"] = p.ProjectedTokens > limit
+		}
+		data["current"] = cur
+	}
+	return OutputJSON(data)
+}
+
+func blocksMD(blocks []store.UsageBlock, active *store.UsageBlock, limit int, now time.Time) error {
+	MDHeader(2, "Usage Blocks")
+
+	if active != nil {
+		p := store.ProjectBlock(active, now)
+		tokens := active.Total().TotalTokens()
+		MDHeader(3, "Current Block")
+		fmt.Printf("- **Started:** %s\n", active.Start.In(Settings.Location()).Format("2006-01-02 15:04"))
+		fmt.Printf("- **Remaining:** %s\n", display.FormatDurationFromTime(p.Remaining))
+		fmt.Printf("- **Tokens:** %s\n", display.FormatTokens(tokens))
+		fmt.Printf("- **Cost:** %s\n", display.FormatCost(active.Cost()))
+		fmt.Printf("- **Burn rate:** %s tok/min\n", display.FormatTokens(int(p.TokensPerMinute)))
+		fmt.Printf("- **Projected:** %s tokens, %s\n", display.FormatTokens(p.ProjectedTokens), display.FormatCost(p.ProjectedCost))
+		if limit > 0 {
+			fmt.Printf("- **Limit:** %s (%s used)\n", display.FormatTokens(limit), percent(tokens, limit))
+		}
+		fmt.Println()
+	}
+
+	if len(blocks) == 0 {
+		fmt.Println("No usage in this range.")
+		fmt.Println()
+		return nil
+	}
+
+	headers := []string{"Start", "Tokens", "Cost", "Models", "Status"}
+	var rows [][]string
+	for i := range blocks {
+		b := &blocks[i]
+		tokens := b.Total().TotalTokens()
+		status := ""
+		if b.IsActive(now) {
+			status = "active"
+		} else if limit > 0 && tokens > limit {
+			status = "over limit"
+		}
+		rows = append(rows, []string{
+			b.Start.In(Settings.Location()).Format("2006-01-02 15:04"),
+			display.FormatTokens(tokens),
+			display.FormatCost(b.Cost()),
+			blockModels(b),
+			status,
+		})
+	}
+	MDTable(headers, rows)
+	return nil
+}
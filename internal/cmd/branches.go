@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// branchGroup holds all branches of one project
+type branchGroup struct {
+	project  string
+	messages int
+	branches []store.BranchStats
+}
+
+func branchesFlags(fs *flag.FlagSet) Runner {
+	project := fs.String("project", "", "Only projects matching `X`")
+	tickets := fs.Bool("tickets", false, "Extract ticket keys (e.g. PROJ-123) from branch names")
+	ticketRegex := fs.String("ticket-regex", "", "Custom ticket key pattern `R` (implies --tickets)")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("branches")
+		}
+		pattern := *ticketRegex
+		if pattern == "" && *tickets {
+			pattern = store.DefaultTicketPattern
+		}
+		return Branches(*project, pattern)
+	}
+}
+
+// Branches reports usage per git branch; a non-empty ticket pattern also
+// groups branches by the ticket keys it extracts
+func Branches(project, pattern string) error {
+	var ticketRe *regexp.Regexp
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid ticket regex: %w", err)
+		}
+		ticketRe = re
+	}
+
+	branches, err := store.ComputeBranchStats(project, ticketRe)
+	if err != nil {
+		return fmt.Errorf("computing branch stats: %w", err)
+	}
+
+	groups := groupBranches(branches)
+	var tickets []store.BranchStats
+	if ticketRe != nil {
+		tickets = store.GroupByTicket(branches)
+	}
+
+	if display.IsJSON() {
+		return branchesJSON(groups, tickets, ticketRe != nil)
+	}
+	if display.IsMD() {
+		return branchesMD(groups, tickets, ticketRe != nil)
+	}
+
+	fmt.Println(display.BoldCyan("Branches"))
+	fmt.Printf("Found %d branches across %d projects\n\n", len(branches), len(groups))
+
+	if len(groups) == 0 {
+		fmt.Println(display.Dim("No sessions found"))
+		return nil
+	}
+
+	for _, g := range groups {
+		display.Box(g.project, func() {
+			maxMsgs := g.branches[0].Messages
+			for _, b := range g.branches {
+				bar := display.Bar(b.Messages, maxMsgs, 15)
+				ticket := ""
+				if b.Ticket != "" {
+					ticket = display.Yellow(" [" + b.Ticket + "]")
+				}
+				fmt.Printf("  %s %-10s  %3d sessions  %8s out  %7s active  %8s wall  %s%s\n",
+					bar,
+					display.FormatNumber(b.Messages)+" msgs",
+					b.Sessions,
+					display.FormatTokens(b.TokensOut),
+					display.FormatDurationFromTime(b.ActiveTime),
+					display.FormatDurationFromTime(b.Duration),
+					display.Bold(branchName(b.Branch)),
+					ticket)
+			}
+		})
+		fmt.Println()
+	}
+
+	if ticketRe != nil {
+		display.Box("Tickets", func() {
+			if len(tickets) == 0 {
+				fmt.Printf("  %s\n", display.Dim("No branch names matched "+ticketRe.String()))
+				return
+			}
+			maxMsgs := tickets[0].Messages
+			for _, t := range tickets {
+				bar := display.Bar(t.Messages, maxMsgs, 15)
+				fmt.Printf("  %s %-10s  %3d sessions  %8s out  %7s active  %8s wall  %s\n",
+					bar,
+					display.FormatNumber(t.Messages)+" msgs",
+					t.Sessions,
+					display.FormatTokens(t.TokensOut),
+					display.FormatDurationFromTime(t.ActiveTime),
+					display.FormatDurationFromTime(t.Duration),
+					display.Bold(t.Ticket))
+			}
+		})
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// groupBranches groups branch stats by project, busiest project first
+func groupBranches(branches []store.BranchStats) []branchGroup {
+	var groups []branchGroup
+	index := make(map[string]int)
+	for _, b := range branches {
+		i, ok := index[b.Project]
+		if !ok {
+			i = len(groups)
+			index[b.Project] = i
+			groups = append(groups, branchGroup{project: b.Project})
+		}
+		groups[i].messages += b.Messages
+		groups[i].branches = append(groups[i].branches, b)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].messages > groups[j].messages
+	})
+	return groups
+}
+
+func branchName(branch string) string {
+	if branch == "" {
+		return "(no branch)"
+	}
+	return branch
+}
+
+type jsonBranch struct {
+	Project    string `json:"project,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	Ticket     string `json:"ticket,omitempty"`
+	Sessions   int    `json:"sessions"`
+	Messages   int    `json:"messages"`
+	TokensIn   int    `json:"inputTokens"`
+	TokensOut  int    `json:"outputTokens"`
+	Duration   string `json:"duration"`
+	DurationMs int64  `json:"durationMs"`
+	Active     string `json:"activeDuration"`
+	ActiveMs   int64  `json:"activeDurationMs"`
+	LastActive string `json:"lastActive,omitempty"`
+}
+
+func toJSONBranch(b store.BranchStats) jsonBranch {
+	la := ""
+	if !b.LastActive.IsZero() {
+		la = b.LastActive.Format(time.RFC3339)
+	}
+	return jsonBranch{
+		Project:    b.Project,
+		Branch:     b.Branch,
+		Ticket:     b.Ticket,
+		Sessions:   b.Sessions,
+		Messages:   b.Messages,
+		TokensIn:   b.TokensIn,
+		TokensOut:  b.TokensOut,
+		Duration:   display.FormatDurationFromTime(b.Duration),
+		DurationMs: b.Duration.Milliseconds(),
+		Active:     display.FormatDurationFromTime(b.ActiveTime),
+		ActiveMs:   b.ActiveTime.Milliseconds(),
+		LastActive: la,
+	}
+}
+
+func branchesJSON(groups []branchGroup, tickets []store.BranchStats, withTickets bool) error {
+	type jsonProject struct {
+		Project  string       `json:"project"`
+		Branches []jsonBranch `json:"branches"`
+	}
+	projects := make([]jsonProject, 0, len(groups))
+	for _, g := range groups {
+		jp := jsonProject{Project: g.project}
+		for _, b := range g.branches {
+			jb := toJSONBranch(b)
+			jb.Project = ""
+			jp.Branches = append(jp.Branches, jb)
+		}
+		projects = append(projects, jp)
+	}
+
+	data := map[string]any{
+		"projects": projects,
+	}
+	if withTickets {
+		out := make([]jsonBranch, 0, len(tickets))
+		for _, t := range tickets {
+			jt := toJSONBranch(t)
+			jt.Branch = ""
+			out = append(out, jt)
+		}
+		data["tickets"] = out
+	}
+	return OutputJSON(data)
+}
+
+func branchesMD(groups []branchGroup, tickets []store.BranchStats, withTickets bool) error {
+	MDHeader(2, "Branches")
+
+	if len(groups) == 0 {
+		fmt.Println("No sessions found.")
+		fmt.Println()
+		return nil
+	}
+
+	headers := []string{"Branch", "Sessions", "Messages", "Tokens Out", "Active", "Wall"}
+	for _, g := range groups {
+		MDHeader(3, g.project)
+		var rows [][]string
+		for _, b := range g.branches {
+			name := branchName(b.Branch)
+			if b.Ticket != "" {
+				name += " [" + b.Ticket + "]"
+			}
+			rows = append(rows, []string{
+				name,
+				fmt.Sprintf("%d", b.Sessions),
+				display.FormatNumber(b.Messages),
+				display.FormatTokens(b.TokensOut),
+				display.FormatDurationFromTime(b.ActiveTime),
+				display.FormatDurationFromTime(b.Duration),
+			})
+		}
+		MDTable(headers, rows)
+	}
+
+	if withTickets {
+		MDHeader(3, "Tickets")
+		if len(tickets) == 0 {
+			fmt.Println("No branch names matched.")
+			fmt.Println()
+			return nil
+		}
+		tHeaders := []string{"Ticket", "Project", "Sessions", "Messages", "Tokens Out", "Active", "Wall"}
+		var tRows [][]string
+		for _, t := range tickets {
+			tRows = append(tRows, []string{
+				t.Ticket,
+				t.Project,
+				fmt.Sprintf("%d", t.Sessions),
+				display.FormatNumber(t.Messages),
+				display.FormatTokens(t.TokensOut),
+				display.FormatDurationFromTime(t.ActiveTime),
+				display.FormatDurationFromTime(t.Duration),
+			})
+		}
+		MDTable(tHeaders, tRows)
+	}
+
+	return nil
+}
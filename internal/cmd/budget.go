@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/dkd/ccs/internal/config"
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// budgetCheck compares usage in one period against one limit
+type budgetCheck struct {
+	Scope  string  `json:"scope"`  // "user" or a project match
+	Period string  `json:"period"` // daily, weekly or monthly
+	Metric string  `json:"metric"` // tokens or cost
+	Used   float64 `json:"used"`
+	Limit  float64 `json:"limit"`
+	Status string  `json:"status"` // ok, warn, over or unavailable
+}
+
+func (c budgetCheck) percent() float64 {
+	if c.Limit <= 0 {
+		return 0
+	}
+	return c.Used * 100 / c.Limit
+}
+
+func (c budgetCheck) format(v float64) string {
+	if c.Metric == "cost" {
+		return display.FormatCost(v)
+	}
+	return display.FormatTokens(int(v))
+}
+
+func budgetFlags(fs *flag.FlagSet) Runner {
+	failOnWarn := fs.Bool("fail-on-warn", false, "Also exit with status 2 when a warning threshold is crossed")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("budget")
+		}
+		return Budget(*failOnWarn)
+	}
+}
+
+// Budget compares current usage against budgets.json. It returns ExitCode(2)
+// when a budget is exceeded (or its warning threshold, with failOnWarn).
+func Budget(failOnWarn bool) error {
+	budgets, err := config.LoadBudgets()
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no budgets configured; create %s, e.g.\n%s", config.BudgetFile(), budgetExample)
+	}
+	if err != nil {
+		return err
+	}
+
+	stats, err := store.LoadStatsCache()
+	if err != nil {
+		return fmt.Errorf("loading stats cache: %w", err)
+	}
+
+	checks := budgetChecks(budgets, stats, time.Now())
+
+	worst := "ok"
+	for _, c := range checks {
+		if c.Status == "over" || (c.Status == "warn" && worst == "ok") {
+			worst = c.Status
+		}
+	}
+
+	if display.IsJSON() {
+		if err := OutputJSON(map[string]any{
+			"warnAt": budgets.WarnAt,
+			"status": worst,
+			"checks": checks,
+		}); err != nil {
+			return err
+		}
+	} else if display.IsMD() {
+		budgetMD(checks)
+	} else {
+		budgetTerminal(checks, stats)
+	}
+
+	if worst == "over" || (failOnWarn && worst == "warn") {
+		return ExitCode(2)
+	}
+	return nil
+}
+
+const budgetExample = `{
+  "daily":   { "tokens": "500K" },
+  "monthly": { "tokens": "10M", "cost": 200 },
+  "projects": {
+    "clientA": { "weekly": { "cost": 50 } }
+  },
+  "warnAt": 80
+}`
+
+// budgetChecks evaluates every configured limit against the stats cache
+func budgetChecks(b *config.Budgets, stats *store.StatsCache, now time.Time) []budgetCheck {
+	var checks []budgetCheck
+
+	add := func(scope, period string, limit *config.Limit, match string) {
+		if limit == nil {
+			return
+		}
+		since := periodStart(periodName(period), now).Format("2006-01-02")
+		if limit.Tokens > 0 {
+			c := budgetCheck{Scope: scope, Period: period, Metric: "tokens", Limit: float64(limit.Tokens)}
+			if match == "" {
+				c.Used = float64(stats.OutputTokensSince(since))
+			} else if stats.HasProjectUsage() {
+				for _, mu := range stats.ProjectUsageSince(since, match) {
+					c.Used += float64(mu.OutputTokens)
+				}
+			} else {
+				c.Status = "unavailable"
+			}
+			checks = append(checks, c)
+		}
+		if limit.Cost > 0 {
+			c := budgetCheck{Scope: scope, Period: period, Metric: "cost", Limit: limit.Cost}
+			if stats.HasProjectUsage() {
+				c.Used = store.EstimateCostByModel(stats.ProjectUsageSince(since, match))
+			} else {
+				c.Status = "unavailable"
+			}
+			checks = append(checks, c)
+		}
+	}
+
+	add("user", "daily", b.Daily, "")
+	add("user", "weekly", b.Weekly, "")
+	add("user", "monthly", b.Monthly, "")
+
+	var names []string
+	for name := range b.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pb := b.Projects[name]
+		add(name, "daily", pb.Daily, name)
+		add(name, "weekly", pb.Weekly, name)
+		add(name, "monthly", pb.Monthly, name)
+	}
+
+	for i := range checks {
+		if checks[i].Status == "unavailable" {
+			continue
+		}
+		switch p := checks[i].percent(); {
+		case p >= 100:
+			checks[i].Status = "over"
+		case p >= b.WarnAt:
+			checks[i].Status = "warn"
+		default:
+			checks[i].Status = "ok"
+		}
+	}
+	return checks
+}
+
+// periodName maps budget periods to the names used by periodStart
+func periodName(period string) string {
+	switch period {
+	case "weekly":
+		return "week"
+	case "monthly":
+		return "month"
+	default:
+		return "today"
+	}
+}
+
+func budgetTerminal(checks []budgetCheck, stats *store.StatsCache) {
+	fmt.Println(display.BoldCyan("Budgets"))
+	fmt.Println()
+
+	if len(checks) == 0 {
+		fmt.Println(display.Dim("No limits configured"))
+		return
+	}
+
+	// Checks are already ordered by scope; print one box per scope
+	for start := 0; start < len(checks); {
+		end := start
+		for end < len(checks) && checks[end].Scope == checks[start].Scope {
+			end++
+		}
+		title := "You"
+		if checks[start].Scope != "user" {
+			title = "Project " + checks[start].Scope
+		}
+		display.Box(title, func() {
+			for _, c := range checks[start:end] {
+				printBudgetCheck(c)
+			}
+		})
+		fmt.Println()
+		start = end
+	}
+
+	if stats.LastComputedDate != time.Now().Format("2006-01-02") {
+		fmt.Printf("%s Stats last computed on %s, run `ccs refresh` for current numbers\n\n",
+			display.Yellow("⚠"), stats.LastComputedDate)
+	}
+}
+
+func printBudgetCheck(c budgetCheck) {
+	label := fmt.Sprintf("  %-8s %-7s", c.Period, c.Metric)
+	if c.Status == "unavailable" {
+		fmt.Printf("%s %s\n", label, display.Dim("needs per-project data, run `ccs refresh`"))
+		return
+	}
+
+	// Bar caps at the limit; overruns are spelled out instead
+	bar := display.Bar(int(c.percent()), 100, 20)
+	detail := fmt.Sprintf("%s / %s  %3.0f%%", c.format(c.Used), c.format(c.Limit), c.percent())
+	switch c.Status {
+	case "over":
+		detail = display.Red(detail + "  +" + c.format(c.Used-c.Limit) + " over")
+	case "warn":
+		detail = display.Yellow(detail)
+	default:
+		detail = display.Green(detail)
+	}
+	fmt.Printf("%s %s %s\n", label, bar, detail)
+}
+
+func budgetMD(checks []budgetCheck) {
+	MDHeader(2, "Budgets")
+
+	if len(checks) == 0 {
+		fmt.Println("No limits configured.")
+		fmt.Println()
+		return
+	}
+
+	headers := []string{"Scope", "Period", "Metric", "Used", "Limit", "%", "Status"}
+	var rows [][]string
+	for _, c := range checks {
+		used, pct := "-", "-"
+		if c.Status != "unavailable" {
+			used = c.format(c.Used)
+			pct = fmt.Sprintf("%.0f%%", c.percent())
+		}
+		rows = append(rows, []string{
+			c.Scope,
+			c.Period,
+			c.Metric,
+			used,
+			c.format(c.Limit),
+			pct,
+			c.Status,
+		})
+	}
+	MDTable(headers, rows)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dkd/ccs/internal/config"
+	"github.com/dkd/ccs/internal/store"
+)
+
+func TestBudgetChecksCountExcludedProjects(t *testing.T) {
+	store.Projects = config.ProjectSettings{Exclude: []string{"*scratch*"}}
+	t.Cleanup(func() { store.Projects = config.ProjectSettings{} })
+
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	today := now.Format("2006-01-02")
+	const model = "claude-sonnet-4-5"
+	usage := func(out int) map[string]map[string]store.ModelUsage {
+		return map[string]map[string]store.ModelUsage{today: {model: {OutputTokens: out}}}
+	}
+	stats := &store.StatsCache{
+		DailyModelTokens: []store.DailyModelTokens{{Date: today, TokensByModel: map[string]int{model: 3_000_000}}},
+		ProjectUsage: map[string]store.ProjectUsage{
+			"-work-shop":   {Path: "/work/shop", Daily: usage(1_000_000)},
+			"-tmp-scratch": {Path: "/tmp/scratch", Daily: usage(2_000_000)},
+		},
+	}
+	b := &config.Budgets{Daily: &config.Limit{Tokens: 10_000_000, Cost: 100}}
+
+	var tokens, cost float64
+	for _, c := range budgetChecks(b, stats, now) {
+		switch c.Metric {
+		case "tokens":
+			tokens = c.Used
+		case "cost":
+			cost = c.Used
+		}
+	}
+	if tokens != 3_000_000 {
+		t.Errorf("user-wide tokens = %v, want 3000000 including the excluded project", tokens)
+	}
+	// Output tokens on Sonnet cost $15 per million
+	if want := tokens / 1_000_000 * store.PriceFor(model).Output; math.Abs(cost-want) > 1e-9 {
+		t.Errorf("user-wide cost = %v, want %v to match the token total", cost, want)
+	}
+
+	// Project matches still leave excluded projects out
+	if got := stats.ProjectUsageSince(today, "/")[model].OutputTokens; got != 1_000_000 {
+		t.Errorf("ProjectUsageSince(%q, \"/\") = %d output tokens, want 1000000", today, got)
+	}
+}
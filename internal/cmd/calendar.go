@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// calendarScale is one color ramp from lowest to highest activity level
+type calendarScale struct {
+	Term []int    // 256-color codes for levels 1-4
+	SVG  []string // fill colors for levels 0-4
+}
+
+var calendarScales = map[string]calendarScale{
+	"green":  {[]int{22, 28, 34, 46}, []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}},
+	"blue":   {[]int{24, 31, 38, 45}, []string{"#ebedf0", "#c6dbef", "#6baed6", "#2171b5", "#08306b"}},
+	"purple": {[]int{54, 91, 128, 165}, []string{"#ebedf0", "#dadaeb", "#9e9ac8", "#6a51a3", "#3f007d"}},
+	"orange": {[]int{94, 130, 166, 208}, []string{"#ebedf0", "#fdd0a2", "#fd8d3c", "#d94801", "#7f2704"}},
+}
+
+// calendarASCII marks levels 0-4 with plain characters
+var calendarASCII = []string{".", "-", "+", "*", "#"}
+
+// calendarShades marks levels 0-4 when colors are off
+var calendarShades = []string{"·", "░", "▒", "▓", "█"}
+
+func calendarFlags(fs *flag.FlagSet) Runner {
+	metric := fs.String("metric", "messages", "Shade by `METRIC`: messages, tokens or sessions")
+	year := fs.Int("year", 0, "Show calendar year `YYYY` (default: last 12 months)")
+	scale := fs.String("scale", "green", "Color `SCALE`: green, blue, purple or orange")
+	ascii := fs.Bool("ascii", false, "Plain ASCII characters instead of colored cells")
+	var svg optionalValue
+	fs.Var(&svg, "svg", "Write an SVG image to stdout or `FILE`")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("calendar")
+		}
+		return Calendar(*metric, *year, *scale, *ascii, svg.set, svg.value)
+	}
+}
+
+func Calendar(metric string, year int, scaleName string, ascii, svg bool, svgPath string) error {
+	weekStart := Settings.FirstWeekday()
+
+	scale, ok := calendarScales[scaleName]
+	if !ok {
+		return fmt.Errorf("invalid --scale %q (want green, blue, purple or orange)", scaleName)
+	}
+
+	stats, err := store.LoadStatsCache()
+	if err != nil {
+		return fmt.Errorf("loading stats cache: %w", err)
+	}
+
+	var series store.DailySeries
+	switch metric {
+	case "messages":
+		series = stats.DailyMessages()
+	case "tokens":
+		series = stats.DailyOutputTokens()
+	case "sessions":
+		series = stats.DailySessions()
+	default:
+		return fmt.Errorf("invalid --metric %q (want messages, tokens or sessions)", metric)
+	}
+
+	// A calendar year, or the 52 weeks up to today
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start, end := today.AddDate(0, 0, -364), today
+	title := "Last 12 Months"
+	if year != 0 {
+		start = time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+		end = time.Date(year, time.December, 31, 0, 0, 0, 0, now.Location())
+		title = strconv.Itoa(year)
+	}
+
+	cal := newCalendar(series, start, end, weekStart)
+
+	if svg {
+		out := cal.svg(metric, scale)
+		if svgPath == "" {
+			fmt.Print(out)
+			return nil
+		}
+		if err := os.WriteFile(svgPath, []byte(out), 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", svgPath, err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", svgPath)
+		return nil
+	}
+	if display.IsJSON() {
+		return cal.json(metric, start, end)
+	}
+	if display.IsMD() {
+		MDHeader(2, "Activity Calendar — "+title)
+		fmt.Printf("%s %s, %s active days\n\n", cal.formatValue(metric, cal.total), metric, display.FormatNumber(cal.activeDays))
+		fmt.Println("```")
+		cal.render(func(level int) string { return calendarASCII[level] })
+		fmt.Println("```")
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println(display.BoldCyan("Activity Calendar — " + title))
+	fmt.Printf("%s %s, %s active days, busiest %s\n\n",
+		display.Bold(cal.formatValue(metric, cal.total)), metric,
+		display.Bold(display.FormatNumber(cal.activeDays)),
+		cal.busiest)
+
+	cal.render(func(level int) string { return heatCell(level, scale, ascii) })
+	fmt.Printf("\n      %s\n\n", heatLegend(scale, ascii))
+	return nil
+}
+
+// heatCell draws one heatmap cell at level 0-4
+func heatCell(level int, scale calendarScale, ascii bool) string {
+	switch {
+	case ascii:
+		return calendarASCII[level]
+	case !display.ColorEnabled():
+		return calendarShades[level]
+	case level == 0:
+		return display.Color256(236, "■")
+	default:
+		return display.Color256(scale.Term[level-1], "■")
+	}
+}
+
+func heatLegend(scale calendarScale, ascii bool) string {
+	var cells []string
+	for level := 0; level <= 4; level++ {
+		cells = append(cells, heatCell(level, scale, ascii))
+	}
+	return display.Dim("Less") + " " + strings.Join(cells, " ") + " " + display.Dim("More")
+}
+
+// heatThresholds splits the non-zero values into quartiles, returning the
+// upper bounds of levels 1-3
+func heatThresholds(values []float64) [3]float64 {
+	var nonZero []float64
+	for _, v := range values {
+		if v > 0 {
+			nonZero = append(nonZero, v)
+		}
+	}
+	sort.Float64s(nonZero)
+
+	var t [3]float64
+	if len(nonZero) > 0 {
+		for i := range t {
+			t[i] = nonZero[len(nonZero)*(i+1)/4]
+		}
+	}
+	return t
+}
+
+// heatLevel maps v to level 0 (none) through 4 (top quartile)
+func heatLevel(thresholds [3]float64, v float64) int {
+	if v <= 0 {
+		return 0
+	}
+	for i, t := range thresholds {
+		if v < t {
+			return i + 1
+		}
+	}
+	return 4
+}
+
+// calendar is a grid of days, one column per week
+type calendar struct {
+	weeks      [][7]calendarDay // weeks[col][row], row 0 is the week start
+	weekStart  time.Weekday
+	thresholds [3]float64 // upper bounds of levels 1-3
+	total      float64
+	activeDays int
+	busiest    string
+}
+
+type calendarDay struct {
+	Date    time.Time
+	Value   float64
+	Level   int
+	InRange bool
+}
+
+func newCalendar(series store.DailySeries, start, end time.Time, weekStart time.Weekday) *calendar {
+	cal := &calendar{weekStart: weekStart}
+
+	var values []float64
+	best := 0.0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		v := series[d.Format("2006-01-02")]
+		values = append(values, v)
+		if v > 0 {
+			cal.total += v
+			cal.activeDays++
+			if v > best {
+				best = v
+				cal.busiest = d.Format("2006-01-02")
+			}
+		}
+	}
+	cal.thresholds = heatThresholds(values)
+
+	// Back up to the first week start on or before start
+	first := start.AddDate(0, 0, -((int(start.Weekday()) - int(weekStart) + 7) % 7))
+	for col := first; !col.After(end); col = col.AddDate(0, 0, 7) {
+		var week [7]calendarDay
+		for row := 0; row < 7; row++ {
+			d := col.AddDate(0, 0, row)
+			v := series[d.Format("2006-01-02")]
+			week[row] = calendarDay{
+				Date:    d,
+				Value:   v,
+				Level:   heatLevel(cal.thresholds, v),
+				InRange: !d.Before(start) && !d.After(end),
+			}
+		}
+		cal.weeks = append(cal.weeks, week)
+	}
+	return cal
+}
+
+func (cal *calendar) formatValue(metric string, v float64) string {
+	if metric == "tokens" {
+		return display.FormatTokens(int(v))
+	}
+	return display.FormatNumber(int(v))
+}
+
+// monthLabels returns the month abbreviations placed above the columns in
+// which each month starts, two characters per column
+func (cal *calendar) monthLabels() string {
+	line := []byte(strings.Repeat(" ", 2*len(cal.weeks)+3))
+	next := 0
+	for col, week := range cal.weeks {
+		for _, d := range week {
+			if d.InRange && d.Date.Day() == 1 {
+				pos := 2 * col
+				if pos >= next {
+					copy(line[pos:], d.Date.Format("Jan"))
+					next = pos + 4
+				}
+				break
+			}
+		}
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+// render prints the grid with weekday labels, drawing each day with cell
+func (cal *calendar) render(cell func(level int) string) {
+	fmt.Printf("      %s\n", cal.monthLabels())
+	for row := 0; row < 7; row++ {
+		wd := time.Weekday((int(cal.weekStart) + row) % 7)
+		label := ""
+		if wd == time.Monday || wd == time.Wednesday || wd == time.Friday {
+			label = wd.String()[:3]
+		}
+		var b strings.Builder
+		for _, week := range cal.weeks {
+			if week[row].InRange {
+				b.WriteString(cell(week[row].Level))
+			} else {
+				b.WriteString(" ")
+			}
+			b.WriteString(" ")
+		}
+		fmt.Printf("  %-3s %s\n", label, strings.TrimRight(b.String(), " "))
+	}
+}
+
+func (cal *calendar) json(metric string, start, end time.Time) error {
+	type jsonDay struct {
+		Date  string  `json:"date"`
+		Value float64 `json:"value"`
+		Level int     `json:"level"`
+	}
+	var days []jsonDay
+	for _, week := range cal.weeks {
+		for _, d := range week {
+			if d.InRange {
+				days = append(days, jsonDay{d.Date.Format("2006-01-02"), d.Value, d.Level})
+			}
+		}
+	}
+	return OutputJSON(map[string]any{
+		"metric":     metric,
+		"from":       start.Format("2006-01-02"),
+		"to":         end.Format("2006-01-02"),
+		"total":      cal.total,
+		"activeDays": cal.activeDays,
+		"busiest":    cal.busiest,
+		"thresholds": cal.thresholds,
+		"days":       days,
+	})
+}
+
+// svg renders the grid as a standalone SVG image
+func (cal *calendar) svg(metric string, scale calendarScale) string {
+	const cellSize, step, left, top = 10, 13, 30, 20
+	width := left + step*len(cal.weeks) + 10
+	height := top + step*7 + 10
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="-apple-system, Helvetica, Arial, sans-serif" font-size="9" fill="#767676">`+"\n", width, height)
+
+	// Month labels along the top, at the column holding the 1st
+	for col, week := range cal.weeks {
+		for _, d := range week {
+			if d.InRange && d.Date.Day() == 1 {
+				fmt.Fprintf(&b, `  <text x="%d" y="%d">%s</text>`+"\n", left+step*col, top-8, d.Date.Format("Jan"))
+				break
+			}
+		}
+	}
+	for row := 0; row < 7; row++ {
+		wd := time.Weekday((int(cal.weekStart) + row) % 7)
+		if wd == time.Monday || wd == time.Wednesday || wd == time.Friday {
+			fmt.Fprintf(&b, `  <text x="0" y="%d">%s</text>`+"\n", top+step*row+cellSize-1, wd.String()[:3])
+		}
+	}
+
+	for col, week := range cal.weeks {
+		for row, d := range week {
+			if !d.InRange {
+				continue
+			}
+			fmt.Fprintf(&b, `  <rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %s %s</title></rect>`+"\n",
+				left+step*col, top+step*row, cellSize, cellSize, scale.SVG[d.Level],
+				d.Date.Format("2006-01-02"), cal.formatValue(metric, d.Value), html.EscapeString(metric))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
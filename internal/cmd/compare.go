@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// compareRange is an inclusive range of local dates
+type compareRange struct {
+	Label string
+	From  time.Time
+	To    time.Time
+}
+
+func (r compareRange) days() int {
+	return int(r.To.Sub(r.From).Hours()/24+0.5) + 1
+}
+
+func (r compareRange) String() string {
+	if r.From.Equal(r.To) {
+		return r.From.Format("2006-01-02")
+	}
+	return r.From.Format("2006-01-02") + ".." + r.To.Format("2006-01-02")
+}
+
+// compareDelta is one metric in both ranges
+type compareDelta struct {
+	A       int      `json:"a"`
+	B       int      `json:"b"`
+	Delta   int      `json:"delta"`
+	Percent *float64 `json:"percent"` // nil when B is zero
+}
+
+func newCompareDelta(a, b int) compareDelta {
+	d := compareDelta{A: a, B: b, Delta: a - b}
+	if b != 0 {
+		p := float64(a-b) * 100 / float64(b)
+		d.Percent = &p
+	}
+	return d
+}
+
+func compareFlags(fs *flag.FlagSet) Runner {
+	toDate := fs.Bool("to-date", false, "Cut B to as many days as have elapsed in A")
+	return func(args []string) error {
+		var specs []string
+		for _, arg := range args {
+			if arg != "vs" {
+				specs = append(specs, arg)
+			}
+		}
+		if len(specs) == 0 {
+			specs = []string{"week"}
+		}
+		if len(specs) > 2 {
+			return usageError("compare")
+		}
+		return Compare(specs, *toDate)
+	}
+}
+
+// Compare reports two periods side by side. specs holds A and optionally B:
+// today, yesterday, week, last-week, month, last-month, YYYY-MM-DD or
+// FROM..TO; B defaults to the period before A.
+func Compare(specs []string, toDate bool) error {
+	now := time.Now()
+	a, err := parseCompareRange(specs[0], now)
+	if err != nil {
+		return err
+	}
+	var b compareRange
+	if len(specs) == 2 {
+		b, err = parseCompareRange(specs[1], now)
+		if err != nil {
+			return err
+		}
+	} else {
+		b = previousRange(a, now)
+	}
+
+	// Only compare as many days of B as have elapsed in A
+	if toDate && b.days() > a.days() {
+		b.To = b.From.AddDate(0, 0, a.days()-1)
+	}
+
+	stats, err := store.LoadStatsCache()
+	if err != nil {
+		return fmt.Errorf("loading stats cache: %w", err)
+	}
+
+	ta := stats.Totals(a.From.Format("2006-01-02"), a.To.Format("2006-01-02"))
+	tb := stats.Totals(b.From.Format("2006-01-02"), b.To.Format("2006-01-02"))
+
+	activity := []struct {
+		Name  string
+		Key   string
+		Delta compareDelta
+	}{
+		{"Sessions", "sessions", newCompareDelta(ta.Sessions, tb.Sessions)},
+		{"Messages", "messages", newCompareDelta(ta.Messages, tb.Messages)},
+		{"Tool calls", "toolCalls", newCompareDelta(ta.ToolCalls, tb.ToolCalls)},
+		{"Output tokens", "outputTokens", newCompareDelta(ta.OutputTokens(), tb.OutputTokens())},
+	}
+
+	models := compareKeys(ta.TokensByModel, tb.TokensByModel, 0)
+	projects := compareKeys(ta.Projects, tb.Projects, 5)
+
+	if display.IsJSON() {
+		deltas := make(map[string]any)
+		for _, row := range activity {
+			deltas[row.Key] = row.Delta
+		}
+		byModel := make(map[string]compareDelta)
+		for _, m := range models {
+			byModel[display.ModelShort(m)] = newCompareDelta(ta.TokensByModel[m], tb.TokensByModel[m])
+		}
+		deltas["tokensByModel"] = byModel
+		if ta.Projects != nil {
+			byProject := make(map[string]compareDelta)
+			for _, p := range projects {
+				byProject[p] = newCompareDelta(ta.Projects[p], tb.Projects[p])
+			}
+			deltas["projects"] = byProject
+		}
+		rangeJSON := func(r compareRange) map[string]string {
+			return map[string]string{
+				"label": r.Label,
+				"from":  r.From.Format("2006-01-02"),
+				"to":    r.To.Format("2006-01-02"),
+			}
+		}
+		return OutputJSON(map[string]any{
+			"a":      rangeJSON(a),
+			"b":      rangeJSON(b),
+			"deltas": deltas,
+		})
+	}
+
+	if display.IsMD() {
+		MDHeader(2, fmt.Sprintf("%s vs. %s", a.Label, b.Label))
+		if a.Label != a.String() || b.Label != b.String() {
+			fmt.Printf("%s vs. %s\n\n", a, b)
+		}
+		headers := []string{"", a.Label, b.Label, "Change"}
+		var rows [][]string
+		add := func(name string, d compareDelta, format func(int) string) {
+			rows = append(rows, []string{name, format(d.A), format(d.B), compareChange(d, format)})
+		}
+		for _, row := range activity {
+			add(row.Name, row.Delta, compareFormat(row.Key))
+		}
+		for _, m := range models {
+			add(display.ModelShort(m), newCompareDelta(ta.TokensByModel[m], tb.TokensByModel[m]), display.FormatTokens)
+		}
+		for _, p := range projects {
+			add(p, newCompareDelta(ta.Projects[p], tb.Projects[p]), display.FormatTokens)
+		}
+		MDTable(headers, rows)
+		return nil
+	}
+
+	fmt.Println(display.BoldCyan(fmt.Sprintf("%s vs. %s", a.Label, b.Label)))
+	if a.Label != a.String() || b.Label != b.String() {
+		fmt.Printf("%s vs. %s\n", a, b)
+	}
+	fmt.Println()
+
+	printRow := func(name string, d compareDelta, format func(int) string) {
+		fmt.Printf("  %-14s %10s  %10s  %s\n",
+			display.Truncate(name, 14), display.Bold(format(d.A)), format(d.B), compareIndicator(d, format))
+	}
+
+	display.Box("Activity", func() {
+		fmt.Printf("  %-14s %10s  %10s\n", "", display.Dim(a.Label), display.Dim(b.Label))
+		for _, row := range activity {
+			printRow(row.Name, row.Delta, compareFormat(row.Key))
+		}
+	})
+	fmt.Println()
+
+	if len(models) > 0 {
+		display.Box("Tokens by Model (output)", func() {
+			for _, m := range models {
+				printRow(display.ModelShort(m), newCompareDelta(ta.TokensByModel[m], tb.TokensByModel[m]), display.FormatTokens)
+			}
+		})
+		fmt.Println()
+	}
+
+	if len(projects) > 0 {
+		display.Box("Top Projects (tokens)", func() {
+			for _, p := range projects {
+				d := newCompareDelta(ta.Projects[p], tb.Projects[p])
+				fmt.Printf("  %10s  %10s  %s  %s\n",
+					display.Bold(display.FormatTokens(d.A)), display.FormatTokens(d.B),
+					compareIndicator(d, display.FormatTokens), p)
+			}
+		})
+		fmt.Println()
+	} else if ta.Projects == nil {
+		fmt.Printf("%s\n\n", display.Dim("Run `ccs refresh` to compare per-project token usage."))
+	}
+
+	return nil
+}
+
+// parseCompareRange parses a named period (today, yesterday, week,
+// last-week, month, last-month), a date or a FROM..TO date range
+func parseCompareRange(spec string, now time.Time) (compareRange, error) {
+	today := periodStart("today", now)
+	switch spec {
+	case "today":
+		return compareRange{"Today", today, today}, nil
+	case "yesterday":
+		d := today.AddDate(0, 0, -1)
+		return compareRange{"Yesterday", d, d}, nil
+	case "week":
+		return compareRange{"This week", periodStart("week", now), today}, nil
+	case "last-week":
+		start := periodStart("week", now).AddDate(0, 0, -7)
+		return compareRange{"Last week", start, start.AddDate(0, 0, 6)}, nil
+	case "month":
+		return compareRange{"This month", periodStart("month", now), today}, nil
+	case "last-month":
+		start := periodStart("month", now).AddDate(0, -1, 0)
+		return compareRange{"Last month", start, start.AddDate(0, 1, -1)}, nil
+	}
+
+	from, to, found := strings.Cut(spec, "..")
+	if !found {
+		to = from
+	}
+	f, err := time.ParseInLocation("2006-01-02", from, now.Location())
+	if err != nil {
+		return compareRange{}, fmt.Errorf("invalid range %q (want today, week, last-week, month, last-month, a date or FROM..TO)", spec)
+	}
+	t, err := time.ParseInLocation("2006-01-02", to, now.Location())
+	if err != nil {
+		return compareRange{}, fmt.Errorf("invalid range %q (want today, week, last-week, month, last-month, a date or FROM..TO)", spec)
+	}
+	if t.Before(f) {
+		return compareRange{}, fmt.Errorf("invalid range %q: end before start", spec)
+	}
+	r := compareRange{From: f, To: t}
+	r.Label = r.String()
+	return r, nil
+}
+
+// previousRange returns the period before r: the previous named period, or
+// the same number of days immediately before a date range
+func previousRange(r compareRange, now time.Time) compareRange {
+	switch r.Label {
+	case "Today":
+		prev, _ := parseCompareRange("yesterday", now)
+		return prev
+	case "This week":
+		prev, _ := parseCompareRange("last-week", now)
+		return prev
+	case "This month":
+		prev, _ := parseCompareRange("last-month", now)
+		return prev
+	}
+	prev := compareRange{
+		From: r.From.AddDate(0, 0, -r.days()),
+		To:   r.From.AddDate(0, 0, -1),
+	}
+	prev.Label = prev.String()
+	return prev
+}
+
+// compareKeys returns the union of keys in a and b, largest combined value
+// first, limited to n entries (0 = all)
+func compareKeys(a, b map[string]int, n int) []string {
+	sum := make(map[string]int)
+	for k, v := range a {
+		sum[k] += v
+	}
+	for k, v := range b {
+		sum[k] += v
+	}
+	var keys []string
+	for k, v := range sum {
+		if v > 0 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if sum[keys[i]] != sum[keys[j]] {
+			return sum[keys[i]] > sum[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if n > 0 && len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+func compareFormat(key string) func(int) string {
+	if key == "outputTokens" {
+		return display.FormatTokens
+	}
+	return display.FormatNumber
+}
+
+// compareChange describes a delta as "+12 (+15.0%)"
+func compareChange(d compareDelta, format func(int) string) string {
+	sign := "+"
+	abs := d.Delta
+	if d.Delta < 0 {
+		sign, abs = "-", -d.Delta
+	}
+	if d.Delta == 0 {
+		return "±0"
+	}
+	if d.Percent == nil {
+		return sign + format(abs) + " (new)"
+	}
+	return fmt.Sprintf("%s%s (%+.1f%%)", sign, format(abs), *d.Percent)
+}
+
+// compareIndicator prefixes compareChange with an up or down arrow
+func compareIndicator(d compareDelta, format func(int) string) string {
+	switch {
+	case d.Delta > 0:
+		return display.Yellow("▲ " + compareChange(d, format))
+	case d.Delta < 0:
+		return display.Cyan("▼ " + compareChange(d, format))
+	default:
+		return display.Dim("= " + compareChange(d, format))
+	}
+}
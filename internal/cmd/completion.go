@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// completeLimit caps the suggestions printed at once, e.g. session IDs
+const completeLimit = 50
+
+// flagValues lists the fixed values of enumerated flags for completion
+var flagValues = map[string][]string{
+	"output":     {"terminal", "json", "md"},
+	"color":      {"auto", "always", "never"},
+	"week-start": {"monday", "sunday"},
+	"sort":       {"messages", "tokens", "sessions", "recent"},
+	"metric":     {"messages", "tokens", "sessions"},
+	"scale":      {"green", "blue", "purple", "orange"},
+}
+
+// completionScripts are thin wrappers that hand the words typed so far to
+// `ccs __complete` and show its "value<TAB>description" lines
+var completionScripts = map[string]string{
+	"bash": `# bash completion for ccs
+# Load with: source <(ccs completion bash)
+_ccs() {
+    local IFS=$'\n' line
+    local -a lines
+    lines=($(ccs __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+    COMPREPLY=()
+    for line in "${lines[@]}"; do
+        COMPREPLY+=("$(printf '%q' "${line%%$'\t'*}")")
+    done
+    if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == *= ]]; then
+        compopt -o nospace
+    fi
+}
+complete -F _ccs ccs
+`,
+	"zsh": `#compdef ccs
+# zsh completion for ccs
+# Load with: source <(ccs completion zsh), or save as _ccs in your $fpath
+_ccs() {
+    local line value desc
+    local -a plain open
+    for line in "${(@f)$(ccs __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z $line ]] && continue
+        value=${line%%$'\t'*}
+        desc=${line#*$'\t'}
+        [[ $desc == "$line" ]] && desc=""
+        value=${value//:/\\:}
+        if [[ $value == *= ]]; then
+            open+=("$value${desc:+:$desc}")
+        else
+            plain+=("$value${desc:+:$desc}")
+        fi
+    done
+    _describe -t values ccs plain
+    _describe -t flags ccs open -S ''
+}
+if [[ $funcstack[1] == _ccs ]]; then
+    _ccs "$@"
+else
+    compdef _ccs ccs
+fi
+`,
+	"fish": `# fish completion for ccs
+# Load with: ccs completion fish | source
+function __ccs_complete
+    set -l words (commandline -opc)
+    ccs __complete $words[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c ccs -f -a '(__ccs_complete)'
+`,
+}
+
+func completionFlags(fs *flag.FlagSet) Runner {
+	return func(args []string) error {
+		if len(args) != 1 {
+			return usageError("completion")
+		}
+		script, ok := completionScripts[args[0]]
+		if !ok {
+			return fmt.Errorf("unknown shell %q (want bash, zsh or fish)", args[0])
+		}
+		fmt.Print(script)
+		return nil
+	}
+}
+
+func completeFlags(fs *flag.FlagSet) Runner {
+	return func(args []string) error {
+		for _, c := range complete(args) {
+			if c.desc == "" {
+				fmt.Println(c.value)
+			} else {
+				fmt.Printf("%s\t%s\n", c.value, c.desc)
+			}
+		}
+		return nil
+	}
+}
+
+type completion struct {
+	value string
+	desc  string
+}
+
+// complete suggests values for the last of words, the arguments typed
+// after "ccs". bash splits "--flag=value" into "--flag", "=" and "value"
+// and replaces only the part after "="; that form gets bare values.
+func complete(words []string) []completion {
+	valueOnly := false
+	n := len(words)
+	switch {
+	case n >= 2 && words[n-1] == "=" && strings.HasPrefix(words[n-2], "-"):
+		words = append(words[:n-2:n-2], words[n-2]+"=")
+		valueOnly = true
+	case n >= 3 && words[n-2] == "=" && strings.HasPrefix(words[n-3], "-"):
+		words = append(words[:n-3:n-3], words[n-3]+"="+words[n-1])
+		valueOnly = true
+	}
+	cur := ""
+	if len(words) > 0 {
+		cur, words = words[len(words)-1], words[:len(words)-1]
+	}
+
+	// Find the command and positional arguments typed so far
+	fs := newFlagSet("ccs")
+	var command *Command
+	var positional []string
+	valueFlag := ""
+	for _, w := range words {
+		if valueFlag != "" {
+			valueFlag = ""
+			continue
+		}
+		if strings.HasPrefix(w, "-") && len(w) > 1 {
+			name := strings.TrimLeft(w, "-")
+			if f := fs.Lookup(name); f != nil && !isBoolFlag(f) {
+				valueFlag = name
+			}
+			continue
+		}
+		if command == nil {
+			c, ok := lookupCommand(w)
+			if !ok || c.Internal {
+				return nil
+			}
+			command = &c
+			fs = newFlagSet("ccs " + c.Name)
+			c.Setup(fs)
+			continue
+		}
+		if len(positional) == 0 {
+			if c, ok := lookupCommand(command.Name + " " + w); ok {
+				command = &c
+				fs = newFlagSet("ccs " + c.Name)
+				c.Setup(fs)
+				continue
+			}
+		}
+		positional = append(positional, w)
+	}
+
+	switch {
+	case valueFlag != "":
+		return filterCompletions(completeFlagValue(valueFlag), cur)
+	case strings.HasPrefix(cur, "-") && strings.Contains(cur, "="):
+		name, value, _ := strings.Cut(strings.TrimLeft(cur, "-"), "=")
+		values := filterCompletions(completeFlagValue(name), value)
+		if !valueOnly {
+			prefix := cur[:len(cur)-len(value)]
+			for i := range values {
+				values[i].value = prefix + values[i].value
+			}
+		}
+		return values
+	case strings.HasPrefix(cur, "-"):
+		return filterCompletions(completeFlagNames(fs), cur)
+	case command == nil:
+		return filterCompletions(completeCommands(), cur)
+	}
+	return filterCompletions(completeArgs(*command, positional), cur)
+}
+
+// completeCommands lists the first word of every command
+func completeCommands() []completion {
+	var out []completion
+	seen := make(map[string]bool)
+	for _, c := range commands {
+		name, _, _ := strings.Cut(c.Name, " ")
+		if c.Internal || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, completion{name, c.Summary})
+	}
+	return out
+}
+
+// completeFlagNames lists a flag set's flags; flags taking a value end in
+// "=" so the shell doesn't add a space
+func completeFlagNames(fs *flag.FlagSet) []completion {
+	var out []completion
+	fs.VisitAll(func(f *flag.Flag) {
+		_, usage := flag.UnquoteUsage(f)
+		name := "--" + f.Name
+		if len(f.Name) == 1 {
+			name = "-" + f.Name
+		} else if !isBoolFlag(f) {
+			name += "="
+		}
+		out = append(out, completion{name, usage})
+	})
+	return out
+}
+
+func completeFlagValue(name string) []completion {
+	if name == "project" {
+		return completeProjects()
+	}
+	var out []completion
+	for _, v := range flagValues[name] {
+		out = append(out, completion{value: v})
+	}
+	return out
+}
+
+// completeArgs suggests a command's positional arguments
+func completeArgs(c Command, positional []string) []completion {
+	var out []completion
+	if len(positional) == 0 {
+		// Second words of two-word commands, e.g. "diff" after "snapshots"
+		for _, sub := range commands {
+			if rest, ok := strings.CutPrefix(sub.Name, c.Name+" "); ok {
+				out = append(out, completion{rest, sub.Summary})
+			}
+		}
+	}
+
+	switch c.Name {
+	case "session":
+		if len(positional) == 0 {
+			out = append(out, completeSessions()...)
+		}
+	case "help":
+		out = append(out, completeCommands()...)
+	case "completion":
+		for _, shell := range []string{"bash", "zsh", "fish"} {
+			out = append(out, completion{value: shell})
+		}
+	case "snapshots diff":
+		if len(positional) < 2 {
+			out = append(out, completion{"latest", "Most recent snapshot"}, completion{"current", "Current stats cache"})
+			list, _ := store.ListSnapshots()
+			for i := len(list) - 1; i >= 0; i-- {
+				out = append(out, completion{value: list[i].Date})
+			}
+		}
+	}
+	return out
+}
+
+// completeSessions lists session IDs, newest first, described by their
+// first prompt and project
+func completeSessions() []completion {
+	sessions, err := store.ListAllSessions("")
+	if err != nil {
+		return nil
+	}
+	out := make([]completion, 0, len(sessions))
+	for _, s := range sessions {
+		prompt := display.Truncate(strings.Join(strings.Fields(s.FirstPrompt), " "), 60)
+		desc := prompt
+		if name := store.ProjectName(s.ProjectPath, ""); name != "" {
+			desc = fmt.Sprintf("%s (%s)", prompt, filepath.Base(name))
+		}
+		out = append(out, completion{s.SessionID, desc})
+	}
+	return out
+}
+
+// completeProjects lists project names for --project: the alias if one is
+// configured, otherwise the last element of the path
+func completeProjects() []completion {
+	projects, err := store.LoadAllProjects()
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var out []completion
+	for _, p := range projects {
+		name := store.Projects.Alias(p.Path, p.DirName)
+		if name == "" {
+			name = filepath.Base(p.Path)
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, completion{name, p.Path})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].value < out[j].value })
+	return out
+}
+
+// filterCompletions keeps the completions starting with prefix, ignoring
+// case, and caps long lists such as session IDs
+func filterCompletions(all []completion, prefix string) []completion {
+	prefix = strings.ToLower(prefix)
+	var out []completion
+	for _, c := range all {
+		if strings.HasPrefix(strings.ToLower(c.value), prefix) {
+			out = append(out, c)
+		}
+		if len(out) == completeLimit {
+			break
+		}
+	}
+	return out
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func completionValues(cs []completion) []string {
+	var out []string
+	for _, c := range cs {
+		out = append(out, c.value)
+	}
+	return out
+}
+
+func TestComplete(t *testing.T) {
+	data := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", data)
+	snapshots := filepath.Join(data, "ccs", "snapshots")
+	if err := os.MkdirAll(snapshots, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, date := range []string{"2026-10-01", "2026-10-02"} {
+		if err := os.WriteFile(filepath.Join(snapshots, date+".json"), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshotRefs := []string{"latest", "current", "2026-10-02", "2026-10-01"}
+
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{"command prefix", []string{"pro"}, []string{"projects"}},
+		{"two-word commands complete their first word once", []string{"snap"}, []string{"snapshots"}},
+		{"unknown command", []string{"nope", ""}, nil},
+		{"internal commands are hidden", []string{"__complete", ""}, nil},
+		{"subcommand", []string{"snapshots", ""}, []string{"diff"}},
+		{"subcommand prefix", []string{"team", "e"}, []string{"export"}},
+		{"subcommand arguments", []string{"snapshots", "diff", ""}, snapshotRefs},
+		{"subcommand after a flag", []string{"snapshots", "--md", "diff", ""}, snapshotRefs},
+		{"second subcommand argument", []string{"snapshots", "diff", "latest", "2026"}, []string{"2026-10-02", "2026-10-01"}},
+		{"no third subcommand argument", []string{"snapshots", "diff", "latest", "current", ""}, nil},
+		{"fixed arguments", []string{"completion", ""}, []string{"bash", "zsh", "fish"}},
+		{"help takes command names", []string{"help", "ti"}, []string{"timesheet"}},
+		{"flag value after a space", []string{"projects", "--sort", "t"}, []string{"tokens"}},
+		{"flag value after =", []string{"projects", "--sort=r"}, []string{"--sort=recent"}},
+		{"flag value split by bash", []string{"projects", "--sort", "=", "s"}, []string{"sessions"}},
+		{"flag value split by bash, empty", []string{"--color", "=", ""}, []string{"auto", "always", "never"}},
+		{"global flag before the command", []string{"--week-start", ""}, []string{"monday", "sunday"}},
+		{"a flag's value isn't the command", []string{"--output", "json", "pro"}, []string{"projects"}},
+		{"value flags end in =", []string{"projects", "--so"}, []string{"--sort="}},
+		{"bool flags don't", []string{"projects", "--by-r"}, []string{"--by-repo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completionValues(complete(tt.words)); !slices.Equal(got, tt.want) {
+				t.Errorf("complete(%q) = %q, want %q", tt.words, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteSubcommandFlags(t *testing.T) {
+	got := completionValues(complete([]string{"team", "export", "-"}))
+	for _, want := range []string{"--user=", "-o", "--json", "--tz="} {
+		if !slices.Contains(got, want) {
+			t.Errorf("team export flags %q lack %q", got, want)
+		}
+	}
+	// --days belongs to team, not team export
+	if slices.Contains(got, "--days=") {
+		t.Errorf("team export flags %q include team's --days", got)
+	}
+}
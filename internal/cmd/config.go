@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/dkd/ccs/internal/config"
+	"github.com/dkd/ccs/internal/display"
+)
+
+// Settings is the effective configuration, set by main before a command
+// runs
+var Settings = config.DefaultSettings()
+
+func configFlags(fs *flag.FlagSet) Runner {
+	return func(args []string) error {
+		if len(args) > 1 || (len(args) == 1 && args[0] != "show") {
+			return fmt.Errorf("unknown config command %q (use show)", args[0])
+		}
+		return Config()
+	}
+}
+
+// Config prints the effective configuration and where each value came from
+func Config() error {
+	type row struct {
+		Key    string `json:"key"`
+		Value  string `json:"value"`
+		Source string `json:"source"`
+		Env    string `json:"env"`
+		Flag   string `json:"flag"`
+	}
+	var rows []row
+	for _, st := range config.SettingsTable {
+		rows = append(rows, row{
+			Key:    st.Key,
+			Value:  st.Value(Settings),
+			Source: Settings.Source(st.Key),
+			Env:    st.Env,
+			Flag:   "--" + st.Flag,
+		})
+	}
+
+	if display.IsJSON() {
+		return OutputJSON(map[string]any{"file": config.SettingsFile(), "settings": rows})
+	}
+
+	if display.IsMD() {
+		MDHeader(1, "Configuration")
+		var md [][]string
+		for _, r := range rows {
+			md = append(md, []string{r.Key, r.Value, r.Source, r.Env, r.Flag})
+		}
+		MDTable([]string{"Setting", "Value", "Source", "Env", "Flag"}, md)
+		return nil
+	}
+
+	fmt.Println()
+	display.Box("Configuration", func() {
+		for _, r := range rows {
+			source := display.Dim(r.Source)
+			if r.Source != "default" {
+				source = display.Cyan(r.Source)
+			}
+			fmt.Printf("  %-16s %s %s\n", r.Key, display.Bold(fmt.Sprintf("%-16s", r.Value)), source)
+		}
+	})
+	fmt.Println()
+	fmt.Printf("  %s\n", display.Dim("File: "+config.SettingsFile()))
+	fmt.Printf("  %s\n\n", display.Dim("Override with CCS_* environment variables or --flag=value, e.g. CCS_TOKENS_DAYS=30 or --tokens-days=30"))
+	return nil
+}
@@ -0,0 +1,11 @@
+package cmd
+
+import "fmt"
+
+// ExitCode is returned by commands that signal a condition through the
+// process exit status rather than an error message
+type ExitCode int
+
+func (e ExitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
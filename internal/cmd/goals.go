@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/config"
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// goalHistory is how many completed periods are checked per goal
+const goalHistory = 4
+
+// goalStatus is a goal evaluated against the current and past periods
+type goalStatus struct {
+	Name    string  `json:"name"`
+	Metric  string  `json:"metric"`
+	Period  string  `json:"period"`
+	Min     *int    `json:"min,omitempty"`
+	Max     *int    `json:"max,omitempty"`
+	Value   float64 `json:"value"`
+	Status  string  `json:"status"`  // met, on track, in progress or missed
+	History []bool  `json:"history"` // completed periods, oldest first
+}
+
+func (g goalStatus) format(v float64) string {
+	if g.Metric == "tokens" {
+		return display.FormatTokens(int(v))
+	}
+	return display.FormatNumber(int(v))
+}
+
+// target describes the goal's bounds, e.g. "≤ 200K" or "≥ 4"
+func (g goalStatus) target() string {
+	var parts []string
+	if g.Min != nil {
+		parts = append(parts, "≥ "+g.format(float64(*g.Min)))
+	}
+	if g.Max != nil {
+		parts = append(parts, "≤ "+g.format(float64(*g.Max)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// evaluateGoals checks every goal for the period containing now and the
+// goalHistory periods before it
+func evaluateGoals(goals []config.Goal, stats *store.StatsCache, now time.Time) []goalStatus {
+	var out []goalStatus
+	for _, g := range goals {
+		gs := goalStatus{Name: g.Title(), Metric: g.Metric, Period: g.Period}
+		if g.Min != nil {
+			n := int(*g.Min)
+			gs.Min = &n
+		}
+		if g.Max != nil {
+			n := int(*g.Max)
+			gs.Max = &n
+		}
+
+		series := goalSeries(g.Metric, stats)
+		met := func(v float64) bool {
+			return (gs.Min == nil || v >= float64(*gs.Min)) && (gs.Max == nil || v <= float64(*gs.Max))
+		}
+
+		for back := goalHistory; back >= 1; back-- {
+			from, to := goalPeriod(g.Period, now, back)
+			gs.History = append(gs.History, met(goalSum(series, from, to)))
+		}
+
+		from, to := goalPeriod(g.Period, now, 0)
+		gs.Value = goalSum(series, from, to)
+		switch {
+		case gs.Max != nil && gs.Value > float64(*gs.Max):
+			gs.Status = "missed"
+		case gs.Min != nil && gs.Value < float64(*gs.Min):
+			gs.Status = "in progress"
+			if gs.Value+goalRemaining(g.Metric, series, now, to) < float64(*gs.Min) {
+				gs.Status = "missed"
+			}
+		case gs.Max != nil:
+			// Staying under a maximum is only settled when the period ends
+			gs.Status = "on track"
+		default:
+			gs.Status = "met"
+		}
+		out = append(out, gs)
+	}
+	return out
+}
+
+// goalSeries returns the daily values a goal metric sums up
+func goalSeries(metric string, stats *store.StatsCache) store.DailySeries {
+	switch metric {
+	case "tokens":
+		return stats.DailyOutputTokens()
+	case "sessions":
+		return stats.DailySessions()
+	case "messages":
+		return stats.DailyMessages()
+	}
+
+	// Active days count 1 per day with messages
+	out := make(store.DailySeries)
+	for date, v := range stats.DailyMessages() {
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil || v <= 0 {
+			continue
+		}
+		if metric == "activeWeekdays" && (d.Weekday() == time.Saturday || d.Weekday() == time.Sunday) {
+			continue
+		}
+		out[date] = 1
+	}
+	return out
+}
+
+// goalPeriod returns the day, week or month back periods before the one
+// containing now, as [from, to)
+func goalPeriod(period string, now time.Time, back int) (time.Time, time.Time) {
+	switch period {
+	case "week":
+		from := periodStart("week", now).AddDate(0, 0, -7*back)
+		return from, from.AddDate(0, 0, 7)
+	case "month":
+		from := periodStart("month", now).AddDate(0, -back, 0)
+		return from, from.AddDate(0, 1, 0)
+	default:
+		from := periodStart("today", now).AddDate(0, 0, -back)
+		return from, from.AddDate(0, 0, 1)
+	}
+}
+
+func goalSum(series store.DailySeries, from, to time.Time) float64 {
+	total := 0.0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		total += series[d.Format("2006-01-02")]
+	}
+	return total
+}
+
+// goalRemaining is the most a count of active days can still grow before
+// to. Other metrics have no upper bound.
+func goalRemaining(metric string, series store.DailySeries, now, to time.Time) float64 {
+	if metric != "activeDays" && metric != "activeWeekdays" {
+		return 1e18
+	}
+	left := 0.0
+	for d := periodStart("today", now); d.Before(to); d = d.AddDate(0, 0, 1) {
+		if series[d.Format("2006-01-02")] > 0 {
+			continue
+		}
+		if metric == "activeWeekdays" && (d.Weekday() == time.Saturday || d.Weekday() == time.Sunday) {
+			continue
+		}
+		left++
+	}
+	return left
+}
+
+// goalMarks renders completed periods as ● (met) and ○ (missed)
+func goalMarks(history []bool) string {
+	var b strings.Builder
+	for _, ok := range history {
+		if ok {
+			b.WriteString("●")
+		} else {
+			b.WriteString("○")
+		}
+	}
+	return b.String()
+}
+
+// goalIcon marks a goal's current status
+func goalIcon(status string) string {
+	switch status {
+	case "met":
+		return display.Green("✓")
+	case "on track":
+		return display.Green("•")
+	case "missed":
+		return display.Red("✗")
+	default:
+		return display.Yellow("…")
+	}
+}
+
+// loadGoalStatus loads goals.json and evaluates it. A missing file means no
+// goals; other errors are returned for the caller to report.
+func loadGoalStatus(stats *store.StatsCache, now time.Time) ([]goalStatus, error) {
+	goals, err := config.LoadGoals()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return evaluateGoals(goals, stats, now), nil
+}
+
+// printStreaksAndGoals renders the dashboard box for streaks and goals
+func printStreaksAndGoals(current, longest int, goals []goalStatus, goalsErr error) {
+	display.Box("Streaks & Goals", func() {
+		fmt.Printf("  Current streak  %s\n", display.Bold(pluralDays(current)))
+		fmt.Printf("  Longest streak  %s\n", display.Bold(pluralDays(longest)))
+		if goalsErr != nil {
+			fmt.Printf("  %s %v\n", display.Yellow("⚠"), goalsErr)
+		}
+		for _, g := range goals {
+			fmt.Printf("  %s %-28s %s %s  %s\n",
+				goalIcon(g.Status),
+				display.Truncate(g.Name, 28),
+				display.Bold(fmt.Sprintf("%8s", g.format(g.Value))),
+				display.Dim(fmt.Sprintf("%-10s %-10s", g.target(), goalPeriodName(g.Period))),
+				display.Dim(goalMarks(g.History)))
+		}
+	})
+}
+
+func goalPeriodName(period string) string {
+	if period == "day" {
+		return "today"
+	}
+	return "this " + period
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
@@ -1,34 +1,133 @@
 package cmd
 
-import "fmt"
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/dkd/ccs/internal/config"
+)
 
 func Help(version string) {
 	fmt.Printf(`ccs %s - Claude Code Summary
 
-Usage: ccs [command] [flags]
+Usage: ccs [global flags] [command] [flags] [args]
 
 Commands:
-  all              Full report (summary + projects + sessions + tokens)
-  summary          Dashboard overview (default)
-  today            Today's activity
-  week             This week's activity
-  month            This month's activity
-  projects         Project ranking by activity
-  sessions         List recent sessions
-  session <id>     Session detail view
-  tokens           Token usage breakdown
-  refresh          Recompute stats cache from session files
-  version          Show version
-  help             Show this help
-
-Global flags:
-  --json           Output as JSON
-  --md             Output as Markdown
-
-Flags (sessions):
-  --project=X      Filter by project name
-  -n N             Limit number of results (default: 20)
-
-Data source: ~/.claude/
 `, version)
+	for _, c := range commands {
+		if c.Internal {
+			continue
+		}
+		printHelpLine(strings.TrimSpace(c.Name+" "+c.Args), c.Summary)
+	}
+
+	fmt.Println("\nGlobal flags (before or after the command):")
+	printFlags(newFlagSet("ccs"), true)
+
+	fmt.Print(`
+Run 'ccs help <command>' for a command's flags.
+
+Data source: ~/.claude/ and the archive in ~/.local/share/ccs/archive/
+Config: ~/.config/ccs/ (config.json, budgets.json, goals.json)
+`)
+}
+
+func helpFlags(fs *flag.FlagSet) Runner {
+	return func(args []string) error {
+		if len(args) == 0 {
+			Help(version)
+			return nil
+		}
+		name := strings.Join(args, " ")
+		c, ok := lookupCommand(name)
+		if !ok {
+			return fmt.Errorf("unknown command %q (see ccs help)", name)
+		}
+		commandHelp(c)
+		return nil
+	}
+}
+
+// commandHelp prints a command's usage line and the flags its Setup
+// registers
+func commandHelp(c Command) {
+	fs := newFlagSet("ccs " + c.Name)
+	c.Setup(fs)
+
+	usage := "ccs " + c.Name
+	if hasCommandFlags(fs) {
+		usage += " [flags]"
+	}
+	if c.Args != "" {
+		usage += " " + c.Args
+	}
+	fmt.Printf("Usage: %s\n\n%s\n", usage, c.Summary)
+
+	if hasCommandFlags(fs) {
+		fmt.Println("\nFlags:")
+		printFlags(fs, false)
+	}
+	fmt.Println("\nGlobal flags: see ccs help")
+}
+
+func hasCommandFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(f *flag.Flag) {
+		if !isGlobalFlag(f.Name) {
+			found = true
+		}
+	})
+	return found
+}
+
+// printFlags lists either the global or the command-specific flags of fs,
+// sorted by name
+func printFlags(fs *flag.FlagSet, global bool) {
+	defaults := config.DefaultSettings()
+	fs.VisitAll(func(f *flag.Flag) {
+		name := f.Name
+		if isGlobalFlag(name) != global {
+			return
+		}
+		valueName, usage := flag.UnquoteUsage(f)
+		optional := false
+		if _, ok := f.Value.(*optionalValue); ok {
+			optional = true
+		} else if isBoolFlag(f) {
+			valueName = ""
+		}
+
+		def := f.DefValue
+		for _, st := range config.SettingsTable {
+			if global && st.Flag == name {
+				def = st.Value(defaults)
+			}
+		}
+		if def != "" && def != "0" && def != "false" && !strings.Contains(usage, "default") {
+			usage += " (default: " + def + ")"
+		}
+
+		label := "-" + name
+		if len(name) > 1 {
+			label = "-" + label
+		}
+		switch {
+		case optional:
+			label += "[=" + valueName + "]"
+		case valueName != "" && len(name) == 1:
+			label += " " + valueName
+		case valueName != "":
+			label += "=" + valueName
+		}
+		printHelpLine(label, usage)
+	})
+}
+
+func printHelpLine(label, text string) {
+	if len(label) > 16 {
+		fmt.Printf("  %s\n  %-16s %s\n", label, "", text)
+		return
+	}
+	fmt.Printf("  %-16s %s\n", label, text)
 }
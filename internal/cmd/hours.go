@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// hoursWeekdays lists matrix rows in display order, Monday first
+var hoursWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+func hoursFlags(fs *flag.FlagSet) Runner {
+	days := fs.Int("days", 90, "Messages from the last `N` days, 0 = all")
+	work := fs.String("work", "9-18", "Working hours `H-H` for the after-hours share")
+	project := fs.String("project", "", "Only projects matching `X`")
+	ascii := fs.Bool("ascii", false, "Plain ASCII characters instead of colored cells")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("hours")
+		}
+		var workStart, workEnd int
+		if _, err := fmt.Sscanf(*work, "%d-%d", &workStart, &workEnd); err != nil ||
+			workStart < 0 || workEnd > 24 || workStart >= workEnd {
+			return fmt.Errorf("invalid --work %q (want e.g. 9-18)", *work)
+		}
+		return Hours(*days, *project, workStart, workEnd, *ascii)
+	}
+}
+
+func Hours(days int, project string, workStart, workEnd int, ascii bool) error {
+	loc := Settings.Location()
+
+	since := time.Time{}
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	m, err := store.ComputeHourMatrix(since, loc, project)
+	if err != nil {
+		return fmt.Errorf("loading sessions: %w", err)
+	}
+
+	// Share of messages outside working hours on weekdays, and on weekends
+	total := m.Total()
+	var afterHours, weekend int
+	bestDay, bestHour, best := time.Monday, 0, 0
+	for wd, row := range m {
+		for h, c := range row {
+			switch {
+			case time.Weekday(wd) == time.Saturday || time.Weekday(wd) == time.Sunday:
+				weekend += c
+			case h < workStart || h >= workEnd:
+				afterHours += c
+			}
+			if c > best {
+				bestDay, bestHour, best = time.Weekday(wd), h, c
+			}
+		}
+	}
+
+	rangeLabel := fmt.Sprintf("Last %d days", days)
+	if days <= 0 {
+		rangeLabel = "All time"
+	}
+
+	if display.IsJSON() {
+		matrix := make(map[string][]int)
+		for _, wd := range hoursWeekdays {
+			matrix[wd.String()[:3]] = m[wd][:]
+		}
+		return OutputJSON(map[string]any{
+			"timezone":   loc.String(),
+			"days":       days,
+			"messages":   total,
+			"matrix":     matrix,
+			"workHours":  fmt.Sprintf("%d-%d", workStart, workEnd),
+			"afterHours": afterHours,
+			"weekend":    weekend,
+		})
+	}
+	if display.IsMD() {
+		MDHeader(2, "Activity by Weekday and Hour")
+		fmt.Printf("%s, %s, %s messages\n\n", rangeLabel, loc, display.FormatNumber(total))
+		headers := []string{"Day"}
+		for h := 0; h < 24; h++ {
+			headers = append(headers, fmt.Sprintf("%02d", h))
+		}
+		var rows [][]string
+		for _, wd := range hoursWeekdays {
+			row := []string{wd.String()[:3]}
+			for _, c := range m[wd] {
+				row = append(row, strconv.Itoa(c))
+			}
+			rows = append(rows, row)
+		}
+		MDTable(headers, rows)
+		fmt.Printf("- **After hours (weekdays outside %d–%d):** %s\n", workStart, workEnd, percent(afterHours, total))
+		fmt.Printf("- **Weekend:** %s\n", percent(weekend, total))
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println(display.BoldCyan("Activity by Weekday and Hour"))
+	fmt.Printf("%s, %s, %s messages\n\n", rangeLabel, loc, display.Bold(display.FormatNumber(total)))
+
+	if total == 0 {
+		fmt.Println(display.Dim("No activity in this range"))
+		return nil
+	}
+
+	var values []float64
+	for _, row := range m {
+		for _, c := range row {
+			values = append(values, float64(c))
+		}
+	}
+	thresholds := heatThresholds(values)
+	scale := calendarScales["green"]
+
+	// Hour labels every three hours, two characters per column
+	header := []byte(strings.Repeat(" ", 48))
+	for h := 0; h < 24; h += 3 {
+		copy(header[2*h:], strconv.Itoa(h))
+	}
+	fmt.Printf("       %s\n", display.Dim(strings.TrimRight(string(header), " ")))
+
+	for _, wd := range hoursWeekdays {
+		var b strings.Builder
+		rowTotal := 0
+		for _, c := range m[wd] {
+			b.WriteString(heatCell(heatLevel(thresholds, float64(c)), scale, ascii))
+			b.WriteString(" ")
+			rowTotal += c
+		}
+		fmt.Printf("  %s  %s %s\n", wd.String()[:3], b.String(), display.Dim(display.FormatNumber(rowTotal)))
+	}
+	fmt.Printf("\n       %s\n\n", heatLegend(scale, ascii))
+
+	display.Box("Patterns", func() {
+		fmt.Printf("  Busiest      %s %02d:00 (%s msgs)\n",
+			display.Bold(bestDay.String()[:3]), bestHour, display.FormatNumber(best))
+		fmt.Printf("  After hours  %s %s\n",
+			display.Bold(percent(afterHours, total)),
+			display.Dim(fmt.Sprintf("weekdays outside %d–%d", workStart, workEnd)))
+		fmt.Printf("  Weekend      %s\n", display.Bold(percent(weekend, total)))
+	})
+	fmt.Println()
+
+	return nil
+}
@@ -15,23 +15,15 @@ func Period(period string) error {
 	}
 
 	now := time.Now()
-	var startDate time.Time
+	startDate := periodStart(period, now)
 	var title string
 
 	switch period {
 	case "today":
-		startDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 		title = "Today"
 	case "week":
-		// Go back to Monday of current week
-		weekday := now.Weekday()
-		if weekday == time.Sunday {
-			weekday = 7
-		}
-		startDate = time.Date(now.Year(), now.Month(), now.Day()-int(weekday)+1, 0, 0, 0, 0, now.Location())
 		title = "This Week"
 	case "month":
-		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 		title = "This Month"
 	}
 
@@ -62,11 +54,16 @@ func Period(period string) error {
 	// Load sessions for this period
 	sessions, sessErr := store.ListSessionsAfter(startDate)
 
+	var forecasts []periodForecast
+	if period != "today" {
+		forecasts = periodForecasts(stats, startDate, periodEnd(period, now), now)
+	}
+
 	if display.IsJSON() {
-		return periodJSON(period, startStr, totalSessions, totalMessages, totalToolCalls, tokensByModel, days, sessions)
+		return periodJSON(period, startStr, totalSessions, totalMessages, totalToolCalls, tokensByModel, days, sessions, forecasts)
 	}
 	if display.IsMD() {
-		return periodMD(title, startStr, totalSessions, totalMessages, totalToolCalls, tokensByModel, days, sessions)
+		return periodMD(title, startStr, totalSessions, totalMessages, totalToolCalls, tokensByModel, days, sessions, forecasts)
 	}
 
 	fmt.Println(display.BoldCyan(title))
@@ -94,6 +91,21 @@ func Period(period string) error {
 		fmt.Println()
 	}
 
+	if len(forecasts) > 0 {
+		display.Box("Forecast (end of "+period+")", func() {
+			for _, pf := range forecasts {
+				f := pf.Forecast
+				fmt.Printf("  %-10s  %s  %s\n",
+					pf.Metric,
+					display.Bold(fmt.Sprintf("~%-8s", pf.format(f.Estimate()))),
+					display.Dim(fmt.Sprintf("range %s – %s  (linear %s, weekday %s)",
+						pf.format(f.Low), pf.format(f.High),
+						pf.format(f.Linear), pf.format(f.Weekday))))
+			}
+		})
+		fmt.Println()
+	}
+
 	// Daily breakdown
 	if len(days) > 1 {
 		display.Box("Daily Breakdown", func() {
@@ -117,7 +129,7 @@ func Period(period string) error {
 
 	if sessErr == nil && len(sessions) > 0 {
 		display.Box("Sessions", func() {
-			limit := 15
+			limit := Settings.PeriodSessions
 			if len(sessions) < limit {
 				limit = len(sessions)
 			}
@@ -147,7 +159,65 @@ func Period(period string) error {
 	return nil
 }
 
-func periodJSON(period, since string, totalSessions, totalMessages, totalToolCalls int, tokensByModel map[string]int, days []store.DailyActivity, sessions []store.SessionEntry) error {
+// periodStart returns local midnight at the start of the day, week (from
+// the configured week start) or month containing now
+func periodStart(period string, now time.Time) time.Time {
+	switch period {
+	case "week":
+		// Go back to the first day of the current week
+		back := (int(now.Weekday()) - int(Settings.FirstWeekday()) + 7) % 7
+		return time.Date(now.Year(), now.Month(), now.Day()-back, 0, 0, 0, 0, now.Location())
+	case "month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+}
+
+// periodEnd returns local midnight after the last day of the period
+func periodEnd(period string, now time.Time) time.Time {
+	start := periodStart(period, now)
+	switch period {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "month":
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// periodForecast is the end-of-period projection of one metric
+type periodForecast struct {
+	Metric   string
+	Forecast store.Forecast
+}
+
+func (pf periodForecast) format(v float64) string {
+	switch pf.Metric {
+	case "cost":
+		return display.FormatCost(v)
+	case "tokens":
+		return display.FormatTokens(int(v))
+	default:
+		return display.FormatNumber(int(v))
+	}
+}
+
+// periodForecasts projects messages, output tokens and (with per-project
+// data in the cache) cost to the end of the period
+func periodForecasts(stats *store.StatsCache, start, end, now time.Time) []periodForecast {
+	forecasts := []periodForecast{
+		{"messages", store.ForecastPeriod(stats.DailyMessages(), start, end, now)},
+		{"tokens", store.ForecastPeriod(stats.DailyOutputTokens(), start, end, now)},
+	}
+	if cost := stats.DailyCost(); cost != nil {
+		forecasts = append(forecasts, periodForecast{"cost", store.ForecastPeriod(cost, start, end, now)})
+	}
+	return forecasts
+}
+
+func periodJSON(period, since string, totalSessions, totalMessages, totalToolCalls int, tokensByModel map[string]int, days []store.DailyActivity, sessions []store.SessionEntry, forecasts []periodForecast) error {
 	shortTokens := make(map[string]int)
 	for model, tokens := range tokensByModel {
 		shortTokens[display.ModelShort(model)] = tokens
@@ -197,10 +267,25 @@ func periodJSON(period, since string, totalSessions, totalMessages, totalToolCal
 		"days":     jsonDays,
 		"sessions": jsonSessions,
 	}
+	if len(forecasts) > 0 {
+		fc := make(map[string]any)
+		for _, pf := range forecasts {
+			f := pf.Forecast
+			fc[pf.Metric] = map[string]float64{
+				"actual":   f.Actual,
+				"estimate": f.Estimate(),
+				"linear":   f.Linear,
+				"weekday":  f.Weekday,
+				"low":      f.Low,
+				"high":     f.High,
+			}
+		}
+		data["forecast"] = fc
+	}
 	return OutputJSON(data)
 }
 
-func periodMD(title, since string, totalSessions, totalMessages, totalToolCalls int, tokensByModel map[string]int, days []store.DailyActivity, sessions []store.SessionEntry) error {
+func periodMD(title, since string, totalSessions, totalMessages, totalToolCalls int, tokensByModel map[string]int, days []store.DailyActivity, sessions []store.SessionEntry, forecasts []periodForecast) error {
 	MDHeader(2, title)
 	fmt.Printf("Since %s\n\n", since)
 
@@ -227,6 +312,24 @@ func periodMD(title, since string, totalSessions, totalMessages, totalToolCalls
 		MDTable(tHeaders, tRows)
 	}
 
+	if len(forecasts) > 0 {
+		MDHeader(3, "Forecast")
+		fHeaders := []string{"Metric", "So far", "Estimate", "Range", "Linear", "Weekday"}
+		var fRows [][]string
+		for _, pf := range forecasts {
+			f := pf.Forecast
+			fRows = append(fRows, []string{
+				pf.Metric,
+				pf.format(f.Actual),
+				pf.format(f.Estimate()),
+				pf.format(f.Low) + " – " + pf.format(f.High),
+				pf.format(f.Linear),
+				pf.format(f.Weekday),
+			})
+		}
+		MDTable(fHeaders, fRows)
+	}
+
 	if len(days) > 1 {
 		MDHeader(3, "Daily Breakdown")
 		dHeaders := []string{"Date", "Messages", "Sessions"}
@@ -245,7 +348,7 @@ func periodMD(title, since string, totalSessions, totalMessages, totalToolCalls
 		MDHeader(3, "Sessions")
 		sHeaders := []string{"ID", "Messages", "Created", "Prompt"}
 		var sRows [][]string
-		limit := 15
+		limit := Settings.PeriodSessions
 		if len(sessions) < limit {
 			limit = len(sessions)
 		}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// errNoTerminal is returned by pickSession when there is no terminal to
+// draw the picker on
+var errNoTerminal = errors.New("the session picker needs a terminal")
+
+func pickFlags(fs *flag.FlagSet) Runner {
+	printID := fs.Bool("print", false, "Print the picked session ID instead of its detail view")
+	project := fs.String("project", "", "Only projects matching `X`")
+	return func(args []string) error {
+		return Pick(*project, strings.Join(args, " "), *printID)
+	}
+}
+
+// Pick lets the user choose a session with a fuzzy filter over prompt,
+// project and branch, starting from query, and shows its detail view or
+// prints its ID
+func Pick(project, query string, printID bool) error {
+	sessions, err := store.ListAllSessions(project)
+	if err != nil {
+		return fmt.Errorf("loading sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return fmt.Errorf("no sessions found")
+	}
+
+	entry, err := pickSession(sessions, query)
+	if err != nil {
+		return err
+	}
+	if printID {
+		fmt.Println(entry.SessionID)
+		return nil
+	}
+	return SessionDetail(entry.SessionID)
+}
+
+// pickItem is one session in the picker with the lowercased text queries
+// are matched against
+type pickItem struct {
+	entry   store.SessionEntry
+	project string
+	created string
+	text    string
+}
+
+func newPickItems(sessions []store.SessionEntry) []pickItem {
+	items := make([]pickItem, 0, len(sessions))
+	for _, s := range sessions {
+		project := store.ProjectName(s.ProjectPath, "")
+		if i := strings.LastIndex(project, "/"); i >= 0 {
+			project = project[i+1:]
+		}
+		created := ""
+		if t, err := time.Parse(time.RFC3339, s.Created); err == nil {
+			created = display.RelativeTime(t)
+		}
+		s.FirstPrompt = strings.Join(strings.Fields(s.FirstPrompt), " ")
+		items = append(items, pickItem{
+			entry:   s,
+			project: project,
+			created: created,
+			text:    strings.ToLower(strings.Join([]string{s.FirstPrompt, project, s.GitBranch, s.SessionID}, " ")),
+		})
+	}
+	return items
+}
+
+// filterPickItems keeps the items matching every word of query, best
+// matches first; ties keep their order (newest first)
+func filterPickItems(items []pickItem, query string) []pickItem {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return items
+	}
+
+	type scored struct {
+		item  pickItem
+		score int
+	}
+	var matches []scored
+	for _, it := range items {
+		total, ok := 0, true
+		for _, term := range terms {
+			s, found := fuzzyScore(it.text, term)
+			if !found {
+				ok = false
+				break
+			}
+			total += s
+		}
+		if ok {
+			matches = append(matches, scored{it, total})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	out := make([]pickItem, len(matches))
+	for i, m := range matches {
+		out[i] = m.item
+	}
+	return out
+}
+
+// fuzzyScore matches term against text as a substring or, failing that,
+// as a subsequence. Substrings score highest, earlier ones higher;
+// subsequences lose points for every character skipped between letters.
+func fuzzyScore(text, term string) (int, bool) {
+	if i := strings.Index(text, term); i >= 0 {
+		return 1000 - min(i, 500), true
+	}
+	score, pos := 500, 0
+	for i, r := range term {
+		j := strings.IndexRune(text[pos:], r)
+		if j < 0 {
+			return 0, false
+		}
+		if i > 0 {
+			score -= j
+		}
+		pos += j + utf8.RuneLen(r)
+	}
+	return max(score, 1), true
+}
+
+// pickSession runs the interactive picker on /dev/tty, so it works while
+// stdout is piped. It returns ExitCode(130) when the user cancels.
+func pickSession(sessions []store.SessionEntry, query string) (*store.SessionEntry, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, errNoTerminal
+	}
+	defer tty.Close()
+
+	state, err := stty(tty, "-g")
+	if err != nil {
+		return nil, errNoTerminal
+	}
+	if _, err := stty(tty, "raw", "-echo"); err != nil {
+		return nil, errNoTerminal
+	}
+	defer stty(tty, strings.TrimSpace(state))
+
+	// Alternate screen, restored on exit
+	fmt.Fprint(tty, "\033[?1049h")
+	defer fmt.Fprint(tty, "\033[?1049l")
+
+	p := &picker{
+		items: newPickItems(sessions),
+		query: []rune(query),
+		rows:  24,
+		cols:  80,
+	}
+	if size, err := stty(tty, "size"); err == nil {
+		if f := strings.Fields(size); len(f) == 2 {
+			if rows, _ := strconv.Atoi(f[0]); rows > 0 {
+				p.rows = rows
+			}
+			if cols, _ := strconv.Atoi(f[1]); cols > 0 {
+				p.cols = cols
+			}
+		}
+	}
+	p.filter()
+
+	buf := make([]byte, 64)
+	for {
+		p.render(tty)
+		n, err := tty.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range splitKeys(buf[:n]) {
+			switch key {
+			case "\r", "\n":
+				if len(p.shown) > 0 {
+					entry := p.shown[p.selected].entry
+					return &entry, nil
+				}
+			case "\x1b", "\x03", "\x07": // Esc, Ctrl-C, Ctrl-G
+				return nil, ExitCode(130)
+			case "\x1b[A", "\x1bOA", "\x10": // Up, Ctrl-P
+				p.move(-1)
+			case "\x1b[B", "\x1bOB", "\x0e": // Down, Ctrl-N
+				p.move(1)
+			case "\x1b[5~": // Page Up
+				p.move(-p.height())
+			case "\x1b[6~": // Page Down
+				p.move(p.height())
+			case "\x7f", "\x08": // Backspace
+				if len(p.query) > 0 {
+					p.query = p.query[:len(p.query)-1]
+					p.filter()
+				}
+			case "\x15": // Ctrl-U
+				p.query = nil
+				p.filter()
+			default:
+				if r, _ := utf8.DecodeRuneInString(key); unicode.IsPrint(r) {
+					p.query = append(p.query, r)
+					p.filter()
+				}
+			}
+		}
+	}
+}
+
+// splitKeys splits one read from the terminal into keys: escape sequences
+// such as "\x1b[A" or single characters. Typing fast or pasting delivers
+// several keys at once.
+func splitKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		n := 1
+		switch {
+		case b[0] == 0x1b && len(b) > 2 && (b[1] == '[' || b[1] == 'O'):
+			// CSI or SS3 sequence, up to its final byte
+			n = 2
+			for n < len(b) && (b[n] < 0x40 || b[n] > 0x7e) {
+				n++
+			}
+			n = min(n+1, len(b))
+		case b[0] >= utf8.RuneSelf:
+			_, n = utf8.DecodeRune(b)
+		}
+		keys = append(keys, string(b[:n]))
+		b = b[n:]
+	}
+	return keys
+}
+
+// picker is the state of one pickSession run
+type picker struct {
+	items    []pickItem
+	shown    []pickItem
+	query    []rune
+	selected int
+	offset   int
+	rows     int
+	cols     int
+}
+
+func (p *picker) filter() {
+	p.shown = filterPickItems(p.items, string(p.query))
+	p.selected, p.offset = 0, 0
+}
+
+// height is the number of list lines below the prompt and counter
+func (p *picker) height() int {
+	return max(p.rows-2, 1)
+}
+
+func (p *picker) move(delta int) {
+	if len(p.shown) == 0 {
+		return
+	}
+	p.selected = min(max(p.selected+delta, 0), len(p.shown)-1)
+	if p.selected < p.offset {
+		p.offset = p.selected
+	}
+	if p.selected >= p.offset+p.height() {
+		p.offset = p.selected - p.height() + 1
+	}
+}
+
+func (p *picker) render(tty *os.File) {
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	fmt.Fprintf(&b, "%s %s\r\n", display.BoldCyan(">"), string(p.query))
+	b.WriteString(display.Dim(fmt.Sprintf("  %d/%d  ↑↓ select, enter open, esc cancel", len(p.shown), len(p.items))))
+
+	end := min(p.offset+p.height(), len(p.shown))
+	for i := p.offset; i < end; i++ {
+		it := p.shown[i]
+		line := fmt.Sprintf("%s  %-10s  %-16s  %s",
+			it.entry.SessionID[:min(8, len(it.entry.SessionID))],
+			it.created,
+			display.Truncate(it.project, 16),
+			it.entry.FirstPrompt)
+		if it.entry.GitBranch != "" {
+			line += " (" + it.entry.GitBranch + ")"
+		}
+		line = truncateRunes(line, p.cols-3)
+		if i == p.selected {
+			fmt.Fprintf(&b, "\r\n%s %s", display.BoldCyan(">"), display.Bold(line))
+		} else {
+			fmt.Fprintf(&b, "\r\n  %s", line)
+		}
+	}
+	// Park the cursor at the end of the query
+	fmt.Fprintf(&b, "\033[1;%dH", 3+len(p.query))
+	fmt.Fprint(tty, b.String())
+}
+
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// stty runs stty against the terminal and returns its output
+func stty(tty *os.File, args ...string) (string, error) {
+	c := exec.Command("stty", args...)
+	c.Stdin = tty
+	out, err := c.Output()
+	return string(out), err
+}
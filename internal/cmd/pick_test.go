@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		text, term string
+		want       int
+		ok         bool
+	}{
+		{"fix login bug", "fix login bug", 1000, true},
+		{"fix login bug", "login", 996, true}, // substring, 4 bytes in
+		{"fix login bug", "", 1000, true},
+		{strings.Repeat("x", 600) + "go", "go", 500, true}, // late substrings bottom out at 500
+		{"abc", "ac", 499, true},                           // subsequence, one byte skipped
+		{"fix login bug", "flb", 492, true},                // gaps after the first letter cost
+		{"café au lait", "éa", 499, true},                  // multi-byte runes advance by their length
+		{"a" + strings.Repeat("x", 600) + "b", "ab", 1, true},
+		{"abc", "ca", 0, false}, // letters out of order
+		{"abc", "abd", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := fuzzyScore(tt.text, tt.term)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("fuzzyScore(%q, %q) = %d, %v, want %d, %v", tt.text, tt.term, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFuzzyScoreRanksSubstringsFirst(t *testing.T) {
+	sub, _ := fuzzyScore(strings.Repeat("x", 600)+"go", "go")
+	seq, _ := fuzzyScore("go", "go") // an exact match
+	fuzzy, _ := fuzzyScore("gxo", "go")
+	if !(seq > sub && sub > fuzzy) {
+		t.Errorf("scores exact=%d late-substring=%d subsequence=%d, want descending", seq, sub, fuzzy)
+	}
+}
+
+func TestSplitKeys(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"abc", []string{"a", "b", "c"}},
+		{"\x1b[A", []string{"\x1b[A"}},             // up arrow
+		{"\x1b[1;5C", []string{"\x1b[1;5C"}},       // ctrl+right, with parameters
+		{"\x1bOP", []string{"\x1bOP"}},             // SS3 F1
+		{"\x1b", []string{"\x1b"}},                 // a lone escape
+		{"\x1b[", []string{"\x1b", "["}},           // too short for a sequence
+		{"\x1b[1;", []string{"\x1b[1;"}},           // truncated sequence
+		{"\x1b\x1b[B", []string{"\x1b", "\x1b[B"}}, // escape, then down arrow
+		{"é", []string{"é"}},
+		{"x\x1b[Bé\r", []string{"x", "\x1b[B", "é", "\r"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := splitKeys([]byte(tt.in)); fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+			t.Errorf("splitKeys(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,24 +1,42 @@
 package cmd
 
 import (
+	"flag"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/dkd/ccs/internal/display"
 	"github.com/dkd/ccs/internal/store"
 )
 
-func Projects() error {
-	projects, err := store.LoadAllProjects()
-	if err != nil {
-		return fmt.Errorf("loading projects: %w", err)
+func projectsFlags(fs *flag.FlagSet) Runner {
+	sortBy := fs.String("sort", "messages", "Sort by `KEY`: messages, tokens, sessions or recent")
+	byRepo := fs.Bool("by-repo", false, "Merge projects in the same git repository or worktree")
+	byGroup := fs.Bool("by-group", false, "Merge projects into the groups from config.json")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("projects")
+		}
+		groupBy := ""
+		switch {
+		case *byRepo && *byGroup:
+			return fmt.Errorf("--by-repo and --by-group can't be combined")
+		case *byRepo:
+			groupBy = "repo"
+		case *byGroup:
+			groupBy = "group"
+		}
+		return Projects(*sortBy, groupBy)
 	}
+}
 
-	// Sort by message count descending
-	sort.Slice(projects, func(i, j int) bool {
-		return projects[i].MessageCount > projects[j].MessageCount
-	})
+func Projects(sortBy, groupBy string) error {
+	projects, err := loadProjects(sortBy, groupBy)
+	if err != nil {
+		return err
+	}
 
 	if display.IsJSON() {
 		return projectsJSON(projects)
@@ -35,53 +53,230 @@ func Projects() error {
 		return nil
 	}
 
-	// Find max for bar chart
-	maxMsgs := projects[0].MessageCount
+	hasUsage := false
+	for _, p := range projects {
+		if p.Usage != nil {
+			hasUsage = true
+			break
+		}
+	}
 
+	// Find max for bar chart
+	maxVal := 0
 	for _, p := range projects {
-		name := p.Path
-		if name == "" {
-			name = p.DirName
+		if v := projectSortValue(p, sortBy); v > maxVal {
+			maxVal = v
 		}
-		bar := display.Bar(p.MessageCount, maxMsgs, 15)
+	}
+
+	for _, p := range projects {
+		name := store.ProjectName(p.Path, p.DirName)
+		bar := display.Bar(projectSortValue(p, sortBy), maxVal, 15)
 		lastActive := display.RelativeTime(p.LastActive)
-		fmt.Printf("  %s %-6s  %3d sessions  %s  %s\n",
+		tokens := ""
+		if hasUsage {
+			tokens = fmt.Sprintf("  %8s", "-")
+			if p.Usage != nil {
+				total := p.Usage.Total()
+				tokens = fmt.Sprintf("  %8s tok  %7s out  %-10s",
+					display.FormatTokens(total.TotalTokens()),
+					display.FormatTokens(total.OutputTokens),
+					display.ModelShort(topModel(p.Usage)))
+			}
+		}
+		fmt.Printf("  %s %-6s  %3d sessions%s  %s  %s\n",
 			bar,
 			display.FormatNumber(p.MessageCount)+" msgs",
 			p.SessionCount,
+			tokens,
 			display.Dim(fmt.Sprintf("%-10s", lastActive)),
-			display.Bold(name))
+			display.Bold(name)+movedNote(p))
+		for _, m := range p.Members {
+			fmt.Printf("      %s\n", display.Dim("└ "+memberName(p.Path, m)))
+		}
 	}
 	fmt.Println()
 
+	if errs := indexErrors(projects); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Printf("%s %v\n", display.Yellow("⚠"), err)
+		}
+		fmt.Printf("%s\n\n", display.Dim("Sessions of these projects were rebuilt from their transcripts."))
+	}
+
+	if !hasUsage {
+		fmt.Printf("%s\n\n", display.Dim("Run `ccs refresh` to compute per-project token usage."))
+	}
+
 	return nil
 }
 
-func projectsJSON(projects []store.Project) error {
-	type jsonProject struct {
-		Path       string `json:"path"`
-		Sessions   int    `json:"sessions"`
-		Messages   int    `json:"messages"`
-		LastActive string `json:"lastActive"`
+// loadProjects loads all projects with their token usage from the stats
+// cache, optionally merged per git repository or config.json group, and
+// sorts them by messages, tokens, sessions or recent activity
+func loadProjects(sortBy, groupBy string) ([]store.Project, error) {
+	switch sortBy {
+	case "messages", "tokens", "sessions", "recent":
+	default:
+		return nil, fmt.Errorf("invalid --sort %q (want messages, tokens, sessions or recent)", sortBy)
+	}
+
+	projects, err := store.LoadAllProjects()
+	if err != nil {
+		return nil, fmt.Errorf("loading projects: %w", err)
+	}
+
+	if stats, err := store.LoadStatsCache(); err == nil {
+		store.AttachProjectUsage(projects, stats)
+	}
+	switch groupBy {
+	case "repo":
+		projects = store.GroupProjectsByRepo(projects)
+	case "group":
+		projects = store.GroupProjectsByConfig(projects)
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		if sortBy == "recent" {
+			return projects[i].LastActive.After(projects[j].LastActive)
+		}
+		return projectSortValue(projects[i], sortBy) > projectSortValue(projects[j], sortBy)
+	})
+	return projects, nil
+}
+
+func projectSortValue(p store.Project, sortBy string) int {
+	switch sortBy {
+	case "tokens":
+		if p.Usage == nil {
+			return 0
+		}
+		return p.Usage.Total().TotalTokens()
+	case "sessions":
+		return p.SessionCount
+	default:
+		return p.MessageCount
+	}
+}
+
+// topModel returns the model with the most output tokens in a project
+func topModel(pu *store.ProjectUsage) string {
+	best, bestOut := "", -1
+	for name, mu := range pu.Models {
+		if mu.OutputTokens > bestOut || (mu.OutputTokens == bestOut && name < best) {
+			best, bestOut = name, mu.OutputTokens
+		}
+	}
+	return best
+}
+
+// indexErrors returns the unreadable sessions-index.json files of projects
+func indexErrors(projects []store.Project) []error {
+	var errs []error
+	for _, p := range projects {
+		if p.IndexErr != nil {
+			errs = append(errs, p.IndexErr)
+		}
+	}
+	return errs
+}
+
+// movedNote points a project whose directory is gone at its likely new path
+func movedNote(p store.Project) string {
+	if p.MovedTo == "" {
+		return ""
+	}
+	if display.IsMD() {
+		return " (moved to " + p.MovedTo + ")"
+	}
+	return display.Dim("  → moved to " + p.MovedTo)
+}
+
+// memberName shows a grouped project by its alias, or by its path relative
+// to the repository root
+func memberName(root string, m store.ProjectMember) string {
+	path := m.Path
+	if name := store.ProjectName(m.Path, m.DirName); name != path {
+		return name
+	}
+	if path == root {
+		return "."
+	}
+	if rel := strings.TrimPrefix(path, root+"/"); rel != path {
+		return rel
 	}
+	return path
+}
+
+func memberPaths(members []store.ProjectMember) []string {
+	var out []string
+	for _, m := range members {
+		out = append(out, m.Path)
+	}
+	return out
+}
+
+func membersNote(p store.Project) string {
+	if len(p.Members) == 0 {
+		return ""
+	}
+	names := make([]string, len(p.Members))
+	for i, m := range p.Members {
+		names[i] = memberName(p.Path, m)
+	}
+	return " (" + strings.Join(names, ", ") + ")"
+}
+
+type jsonProject struct {
+	Name       string                    `json:"name"`
+	Path       string                    `json:"path"`
+	Sessions   int                       `json:"sessions"`
+	Messages   int                       `json:"messages"`
+	LastActive string                    `json:"lastActive"`
+	MovedTo    string                    `json:"movedTo,omitempty"`
+	IndexError string                    `json:"indexError,omitempty"`
+	Members    []string                  `json:"members,omitempty"`
+	Tokens     map[string]int            `json:"tokens,omitempty"`
+	Models     map[string]map[string]int `json:"models,omitempty"`
+}
+
+func toJSONProjects(projects []store.Project) []jsonProject {
 	out := make([]jsonProject, 0, len(projects))
 	for _, p := range projects {
-		name := p.Path
-		if name == "" {
-			name = p.DirName
+		path := p.Path
+		if path == "" {
+			path = p.DirName
 		}
 		la := ""
 		if !p.LastActive.IsZero() {
 			la = p.LastActive.Format(time.RFC3339)
 		}
-		out = append(out, jsonProject{
-			Path:       name,
+		jp := jsonProject{
+			Name:       store.ProjectName(p.Path, p.DirName),
+			Path:       path,
 			Sessions:   p.SessionCount,
 			Messages:   p.MessageCount,
 			LastActive: la,
-		})
+			MovedTo:    p.MovedTo,
+			Members:    memberPaths(p.Members),
+		}
+		if p.IndexErr != nil {
+			jp.IndexError = p.IndexErr.Error()
+		}
+		if p.Usage != nil {
+			jp.Tokens = usageMap(p.Usage.Total())
+			jp.Models = make(map[string]map[string]int)
+			for model, mu := range p.Usage.Models {
+				jp.Models[display.ModelShort(model)] = usageMap(mu)
+			}
+		}
+		out = append(out, jp)
 	}
-	return OutputJSON(out)
+	return out
+}
+
+func projectsJSON(projects []store.Project) error {
+	return OutputJSON(toJSONProjects(projects))
 }
 
 func projectsMD(projects []store.Project) error {
@@ -94,21 +289,35 @@ func projectsMD(projects []store.Project) error {
 		return nil
 	}
 
-	headers := []string{"Project", "Sessions", "Messages", "Last Active"}
+	headers := []string{"Project", "Sessions", "Messages", "Input", "Output", "Cache Read", "Cache Creation", "Top Model", "Last Active"}
 	var rows [][]string
 	for _, p := range projects {
-		name := p.Path
-		if name == "" {
-			name = p.DirName
-		}
+		name := store.ProjectName(p.Path, p.DirName)
 		lastActive := display.RelativeTime(p.LastActive)
+		in, out, cacheRead, cacheCreate, model := "-", "-", "-", "-", "-"
+		if p.Usage != nil {
+			total := p.Usage.Total()
+			in = display.FormatTokens(total.InputTokens)
+			out = display.FormatTokens(total.OutputTokens)
+			cacheRead = display.FormatTokens(total.CacheReadInputTokens)
+			cacheCreate = display.FormatTokens(total.CacheCreationInputTokens)
+			model = display.ModelShort(topModel(p.Usage))
+		}
 		rows = append(rows, []string{
-			name,
+			name + movedNote(p) + membersNote(p),
 			fmt.Sprintf("%d", p.SessionCount),
 			display.FormatNumber(p.MessageCount),
+			in,
+			out,
+			cacheRead,
+			cacheCreate,
+			model,
 			lastActive,
 		})
 	}
 	MDTable(headers, rows)
+	for _, err := range indexErrors(projects) {
+		fmt.Printf("> ⚠ %v\n\n", err)
+	}
 	return nil
 }
@@ -10,7 +10,7 @@ import (
 func Refresh() error {
 	fmt.Println(display.BoldCyan("Refreshing stats cache..."))
 
-	stats, err := store.ComputeStats(func(done, total int) {
+	stats, skipped, err := store.ComputeStats(func(done, total int) {
 		fmt.Printf("\r  Scanning... %d/%d sessions", done, total)
 	})
 	if err != nil {
@@ -26,7 +26,20 @@ func Refresh() error {
 		display.Green("Done."),
 		display.Bold(display.FormatNumber(stats.TotalSessions)),
 		display.Bold(display.FormatNumber(stats.TotalMessages)))
-	fmt.Printf("  Cache updated for %s\n\n", display.Bold(stats.LastComputedDate))
+	fmt.Printf("  Cache updated for %s\n", display.Bold(stats.LastComputedDate))
+	if len(skipped) > 0 {
+		fmt.Printf("  %s %d session files skipped, their sessions are missing from the totals:\n",
+			display.Yellow("⚠"), len(skipped))
+		for _, err := range skipped {
+			fmt.Printf("    %s\n", display.Dim(err.Error()))
+		}
+	}
+	if path, err := store.SaveSnapshot(stats); err != nil {
+		fmt.Printf("  %s snapshot not saved: %v\n", display.Yellow("⚠"), err)
+	} else {
+		fmt.Printf("  Snapshot  %s\n", display.Dim(path))
+	}
+	fmt.Println()
 
 	return nil
 }
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/config"
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// Runner runs a command with the positional arguments left after its flags
+// were parsed
+type Runner func(args []string) error
+
+// Command is a ccs subcommand. Setup registers the command's flags on fs
+// and returns the function that runs it; global flags are added to every
+// command's flag set.
+type Command struct {
+	Name    string // one or two words, e.g. "snapshots diff"
+	Args    string // positional arguments for usage lines, e.g. "<id>"
+	Summary string
+	Aliases []string
+	Setup   func(fs *flag.FlagSet) Runner
+
+	// Internal commands are left out of help and completion and get their
+	// arguments unparsed
+	Internal bool
+}
+
+// commands lists every command in the order `ccs help` shows them
+var commands []Command
+
+func init() {
+	commands = []Command{
+		{Name: "all", Summary: "Full report (summary + projects + sessions + tokens)", Setup: noFlags(All)},
+		{Name: "summary", Summary: "Dashboard overview (default)", Setup: noFlags(Summary)},
+		{Name: "today", Summary: "Today's activity", Setup: noFlags(func() error { return Period("today") })},
+		{Name: "week", Summary: "This week's activity and forecast", Setup: noFlags(func() error { return Period("week") })},
+		{Name: "month", Summary: "This month's activity and forecast", Setup: noFlags(func() error { return Period("month") })},
+		{Name: "projects", Summary: "Project ranking by activity", Setup: projectsFlags},
+		{Name: "sessions", Summary: "List recent sessions", Setup: sessionsFlags},
+		{Name: "session", Args: "[id]", Summary: "Session detail view (picker if the ID is missing or ambiguous)", Setup: sessionDetailFlags},
+		{Name: "pick", Args: "[query]", Summary: "Fuzzy-find a session by prompt, project or branch", Setup: pickFlags},
+		{Name: "tokens", Summary: "Token usage breakdown", Setup: noFlags(Tokens)},
+		{Name: "wrapped", Args: "[year]", Summary: "Year in review (default: this year)", Setup: wrappedFlags},
+		{Name: "compare", Args: "[A] [B]", Summary: "Compare two periods (default: week vs. last-week)", Setup: compareFlags},
+		{Name: "calendar", Summary: "Year-long activity heatmap", Setup: calendarFlags},
+		{Name: "hours", Summary: "Weekday × hour activity heatmap", Setup: hoursFlags},
+		{Name: "branches", Summary: "Usage per git branch and ticket", Setup: branchesFlags},
+		{Name: "timesheet", Summary: "Active time per project per day", Setup: timesheetFlags},
+		{Name: "blocks", Summary: "5-hour usage blocks with burn rate projection", Setup: blocksFlags},
+		{Name: "budget", Summary: "Usage against configured budgets", Setup: budgetFlags},
+		{Name: "refresh", Summary: "Recompute stats cache from session files", Setup: noFlags(Refresh)},
+		{Name: "archive", Summary: "Copy session files into the ccs archive", Setup: archiveFlags},
+		{Name: "snapshots", Args: "[list]", Summary: "List the daily stats snapshots saved by refresh", Setup: snapshotsFlags},
+		{Name: "snapshots diff", Args: "<a> [b]", Summary: "Compare two snapshots (dates, latest or current)", Setup: snapshotsDiffFlags},
+		{Name: "config", Args: "[show]", Summary: "Print the effective configuration and its sources", Setup: configFlags},
+		{Name: "verify", Summary: "Show duplicate token entries removed from totals", Setup: noFlags(Verify)},
+		{Name: "team", Args: "<dir>", Summary: "Team report from exported bundles in <dir>", Setup: teamFlags},
+		{Name: "team export", Summary: "Export an anonymized bundle of your stats", Setup: teamExportFlags},
+		{Name: "completion", Args: "bash|zsh|fish", Summary: "Print a shell completion script", Setup: completionFlags},
+		{Name: "__complete", Args: "<words>", Summary: "Suggest completions for the words typed so far", Setup: completeFlags, Internal: true},
+		{Name: "version", Summary: "Show version", Aliases: []string{"--version", "-v"}, Setup: noFlags(func() error {
+			fmt.Printf("ccs %s\n", version)
+			return nil
+		})},
+		{Name: "help", Args: "[command]", Summary: "Show this help, or a command's flags", Aliases: []string{"--help", "-h"}, Setup: helpFlags},
+	}
+}
+
+// version is set by Run for the version and help commands
+var version = "dev"
+
+// Run parses global flags, picks the command named by the first argument
+// (summary if there is none), parses its flags and runs it
+func Run(ver string, args []string) error {
+	version = ver
+
+	if len(args) > 0 {
+		if c, ok := lookupAlias(args[0]); ok {
+			return runCommand(c, args[1:])
+		}
+	}
+
+	globals := newFlagSet("ccs")
+	if err := globals.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			Help(version)
+			return nil
+		}
+		return fmt.Errorf("%w (see ccs help)", err)
+	}
+	rest := globals.Args()
+
+	name := "summary"
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+	c, ok := lookupCommand(name)
+	if !ok {
+		return fmt.Errorf("unknown command %q (see ccs help)", name)
+	}
+	return runCommand(c, rest)
+}
+
+func runCommand(c Command, args []string) error {
+	fs := newFlagSet("ccs " + c.Name)
+	run := c.Setup(fs)
+	if c.Internal {
+		applySettings()
+		return run(args)
+	}
+
+	// A subcommand's name may follow flags given to the parent, as in
+	// `ccs snapshots --md diff latest`
+	if fs.Parse(args) == nil && fs.NArg() > 0 {
+		if sub, ok := lookupCommand(c.Name + " " + fs.Arg(0)); ok {
+			i := len(args) - fs.NArg()
+			return runCommand(sub, append(args[:i:i], args[i+1:]...))
+		}
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		commandHelp(c)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w (see ccs help %s)", c.Name, err, c.Name)
+	}
+	applySettings()
+	return run(positional)
+}
+
+// newFlagSet returns a silent flag set with the global flags registered;
+// errors are reported by Run and help is generated by commandHelp
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolFunc("json", "Output as JSON", func(string) error {
+		Settings.SetOutput("json", "--json")
+		return nil
+	})
+	fs.BoolFunc("md", "Output as Markdown", func(string) error {
+		Settings.SetOutput("md", "--md")
+		return nil
+	})
+	fs.Func("idle", "Pauses longer than `DUR` count as idle, not active time (default: 30m)", func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		store.IdleGap = d
+		return nil
+	})
+	for _, st := range config.SettingsTable {
+		st := st
+		fs.Func(st.Flag, st.Help, func(v string) error {
+			return Settings.SetFlag(st.Flag, v)
+		})
+	}
+	return fs
+}
+
+// isGlobalFlag reports whether a flag is registered by newFlagSet
+func isGlobalFlag(name string) bool {
+	return newFlagSet("").Lookup(name) != nil
+}
+
+// parseInterspersed parses flags anywhere among the positional arguments,
+// which the flag package stops at. Everything after "--" is positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// applySettings hands the effective settings to the display and store
+// packages once all flags are parsed
+func applySettings() {
+	display.OutputFormat = ""
+	if Settings.Output != "terminal" {
+		display.OutputFormat = Settings.Output
+	}
+	display.SetColorMode(Settings.Color)
+	store.Projects = Settings.Projects
+}
+
+func lookupCommand(name string) (Command, bool) {
+	for _, c := range commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+func lookupAlias(arg string) (Command, bool) {
+	for _, c := range commands {
+		for _, a := range c.Aliases {
+			if a == arg {
+				return c, true
+			}
+		}
+	}
+	return Command{}, false
+}
+
+// noFlags adapts a command without flags or arguments
+func noFlags(run func() error) func(fs *flag.FlagSet) Runner {
+	return func(fs *flag.FlagSet) Runner {
+		return func(args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unexpected argument %q (see ccs help)", args[0])
+			}
+			return run()
+		}
+	}
+}
+
+// usageError reports wrong positional arguments with the command's usage
+func usageError(name string) error {
+	c, _ := lookupCommand(name)
+	return fmt.Errorf("usage: ccs %s", strings.TrimSpace(c.Name+" "+c.Args))
+}
+
+// optionalValue is a flag that works both alone (--svg) and with a value
+// (--svg=FILE)
+type optionalValue struct {
+	set   bool
+	value string
+}
+
+func (o *optionalValue) String() string { return o.value }
+
+func (o *optionalValue) Set(v string) error {
+	o.set = true
+	if v != "true" {
+		o.value = v
+	}
+	return nil
+}
+
+func (o *optionalValue) IsBoolFlag() bool { return true }
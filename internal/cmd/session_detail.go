@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"sort"
 	"time"
@@ -9,6 +11,32 @@ import (
 	"github.com/dkd/ccs/internal/store"
 )
 
+func sessionDetailFlags(fs *flag.FlagSet) Runner {
+	return func(args []string) error {
+		switch len(args) {
+		case 0:
+			return Pick("", "", false)
+		case 1:
+		default:
+			return usageError("session")
+		}
+
+		err := SessionDetail(args[0])
+		var ambiguous *store.AmbiguousSessionError
+		if !errors.As(err, &ambiguous) {
+			return err
+		}
+		entry, pickErr := pickSession(ambiguous.Matches, "")
+		if errors.Is(pickErr, errNoTerminal) {
+			return err
+		}
+		if pickErr != nil {
+			return pickErr
+		}
+		return SessionDetail(entry.SessionID)
+	}
+}
+
 func SessionDetail(idPrefix string) error {
 	// Find session file by prefix match
 	path, entry, err := store.FindSession(idPrefix)
@@ -35,7 +63,7 @@ func SessionDetail(idPrefix string) error {
 	display.Box("Info", func() {
 		fmt.Printf("  ID          %s\n", display.Bold(detail.ID))
 		if entry != nil && entry.ProjectPath != "" {
-			fmt.Printf("  Project     %s\n", entry.ProjectPath)
+			fmt.Printf("  Project     %s\n", store.ProjectName(entry.ProjectPath, ""))
 		}
 		if detail.GitBranch != "" {
 			fmt.Printf("  Branch      %s\n", detail.GitBranch)
@@ -47,8 +75,8 @@ func SessionDetail(idPrefix string) error {
 			fmt.Printf("  Started     %s\n", detail.StartedAt.Format(time.RFC3339))
 		}
 		if !detail.EndedAt.IsZero() {
-			duration := detail.EndedAt.Sub(detail.StartedAt)
-			fmt.Printf("  Duration    %s\n", display.Bold(display.FormatDurationFromTime(duration)))
+			fmt.Printf("  Active      %s\n", display.Bold(display.FormatDurationFromTime(detail.ActiveDuration())))
+			fmt.Printf("  Wall        %s\n", display.FormatDurationFromTime(detail.WallDuration()))
 		}
 		if detail.Model != "" {
 			fmt.Printf("  Model       %s\n", display.Bold(display.ModelShort(detail.Model)))
@@ -111,7 +139,7 @@ func SessionDetail(idPrefix string) error {
 			if !msg.Timestamp.IsZero() {
 				ts = msg.Timestamp.Format("15:04") + " "
 			}
-			prompt := display.Truncate(msg.Content, 70)
+			prompt := display.Truncate(msg.Content, Settings.Truncate)
 			fmt.Printf("  %s%s %s\n",
 				display.Dim(ts),
 				display.Green("▸"),
@@ -171,7 +199,7 @@ func sessionDetailJSON(detail *store.SessionDetail, entry *store.SessionEntry) e
 	}
 
 	if entry != nil && entry.ProjectPath != "" {
-		data["project"] = entry.ProjectPath
+		data["project"] = store.ProjectName(entry.ProjectPath, "")
 	}
 	if detail.GitBranch != "" {
 		data["branch"] = detail.GitBranch
@@ -180,8 +208,8 @@ func sessionDetailJSON(detail *store.SessionDetail, entry *store.SessionEntry) e
 		data["started"] = detail.StartedAt.Format(time.RFC3339)
 	}
 	if !detail.EndedAt.IsZero() {
-		duration := detail.EndedAt.Sub(detail.StartedAt)
-		data["duration"] = display.FormatDurationFromTime(duration)
+		data["duration"] = display.FormatDurationFromTime(detail.WallDuration())
+		data["activeDuration"] = display.FormatDurationFromTime(detail.ActiveDuration())
 	}
 
 	return OutputJSON(data)
@@ -193,7 +221,7 @@ func sessionDetailMD(detail *store.SessionDetail, entry *store.SessionEntry) err
 	MDHeader(3, "Info")
 	fmt.Printf("- **ID:** %s\n", detail.ID)
 	if entry != nil && entry.ProjectPath != "" {
-		fmt.Printf("- **Project:** %s\n", entry.ProjectPath)
+		fmt.Printf("- **Project:** %s\n", store.ProjectName(entry.ProjectPath, ""))
 	}
 	if detail.GitBranch != "" {
 		fmt.Printf("- **Branch:** %s\n", detail.GitBranch)
@@ -205,8 +233,8 @@ func sessionDetailMD(detail *store.SessionDetail, entry *store.SessionEntry) err
 		fmt.Printf("- **Started:** %s\n", detail.StartedAt.Format(time.RFC3339))
 	}
 	if !detail.EndedAt.IsZero() {
-		duration := detail.EndedAt.Sub(detail.StartedAt)
-		fmt.Printf("- **Duration:** %s\n", display.FormatDurationFromTime(duration))
+		fmt.Printf("- **Active:** %s\n", display.FormatDurationFromTime(detail.ActiveDuration()))
+		fmt.Printf("- **Wall:** %s\n", display.FormatDurationFromTime(detail.WallDuration()))
 	}
 	if detail.Model != "" {
 		fmt.Printf("- **Model:** %s\n", display.ModelShort(detail.Model))
@@ -258,7 +286,7 @@ func sessionDetailMD(detail *store.SessionDetail, entry *store.SessionEntry) err
 		if !msg.Timestamp.IsZero() {
 			ts = msg.Timestamp.Format("15:04") + " "
 		}
-		prompt := display.Truncate(msg.Content, 70)
+		prompt := display.Truncate(msg.Content, Settings.Truncate)
 		fmt.Printf("- %s**>** %s\n", ts, prompt)
 		shown++
 	}
@@ -1,32 +1,32 @@
 package cmd
 
 import (
+	"flag"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/dkd/ccs/internal/display"
 	"github.com/dkd/ccs/internal/store"
 )
 
-func Sessions(args []string) error {
-	var project string
-	limit := 20
-
-	for i := 0; i < len(args); i++ {
-		switch {
-		case strings.HasPrefix(args[i], "--project="):
-			project = strings.TrimPrefix(args[i], "--project=")
-		case args[i] == "-n" && i+1 < len(args):
-			n, err := strconv.Atoi(args[i+1])
-			if err == nil {
-				limit = n
-			}
-			i++
+func sessionsFlags(fs *flag.FlagSet) Runner {
+	project := fs.String("project", "", "Only projects matching `X`")
+	limit := fs.Int("n", 0, "List `N` sessions (default: sessionsLimit setting)")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("sessions")
 		}
+		if *limit < 0 {
+			return fmt.Errorf("invalid -n %d", *limit)
+		}
+		if *limit == 0 {
+			*limit = Settings.SessionsLimit
+		}
+		return Sessions(*project, *limit)
 	}
+}
 
+func Sessions(project string, limit int) error {
 	sessions, err := store.ListAllSessions(project)
 	if err != nil {
 		return fmt.Errorf("loading sessions: %w", err)
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// snapshotRemovedLimit caps the removed sessions listed in terminal output
+const snapshotRemovedLimit = 20
+
+// snapshotsFlags lists the dated stats snapshots saved by `ccs refresh`
+func snapshotsFlags(fs *flag.FlagSet) Runner {
+	return func(args []string) error {
+		if len(args) > 1 || (len(args) == 1 && args[0] != "list") {
+			return fmt.Errorf("unknown snapshots command %q (use list or diff)", args[0])
+		}
+		return snapshotsList()
+	}
+}
+
+// snapshotsDiffFlags compares two snapshots; the second defaults to the
+// current stats cache
+func snapshotsDiffFlags(fs *flag.FlagSet) Runner {
+	return func(args []string) error {
+		switch len(args) {
+		case 1:
+			return snapshotsDiff(args[0], "current")
+		case 2:
+			return snapshotsDiff(args[0], args[1])
+		default:
+			return usageError("snapshots diff")
+		}
+	}
+}
+
+func snapshotsList() error {
+	list, err := store.ListSnapshots()
+	if err != nil {
+		return fmt.Errorf("reading snapshots: %w", err)
+	}
+
+	type row struct {
+		Date         string `json:"date"`
+		Sessions     int    `json:"sessions"`
+		Messages     int    `json:"messages"`
+		OutputTokens int    `json:"outputTokens"`
+		Bytes        int64  `json:"bytes"`
+	}
+	var rows []row
+	for _, info := range list {
+		snap, err := store.LoadSnapshot(info.Date)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, row{
+			Date:         info.Date,
+			Sessions:     snap.Stats.TotalSessions,
+			Messages:     snap.Stats.TotalMessages,
+			OutputTokens: snapshotOutputTokens(snap),
+			Bytes:        info.Size,
+		})
+	}
+
+	if display.IsJSON() {
+		return OutputJSON(map[string]any{"dir": store.SnapshotDir(), "snapshots": rows})
+	}
+
+	if display.IsMD() {
+		MDHeader(1, "Snapshots")
+		var md [][]string
+		for _, r := range rows {
+			md = append(md, []string{r.Date,
+				display.FormatNumber(r.Sessions),
+				display.FormatNumber(r.Messages),
+				display.FormatTokens(r.OutputTokens)})
+		}
+		MDTable([]string{"Date", "Sessions", "Messages", "Output Tokens"}, md)
+		return nil
+	}
+
+	fmt.Printf("\n%s\n\n", display.BoldCyan("Snapshots"))
+	if len(rows) == 0 {
+		fmt.Printf("  %s\n\n", display.Dim("No snapshots yet. `ccs refresh` saves one per day."))
+		return nil
+	}
+	fmt.Printf("  %-12s %10s %10s %12s %10s\n", "Date", "Sessions", "Messages", "Output", "Size")
+	for _, r := range rows {
+		fmt.Printf("  %-12s %10s %10s %12s %10s\n", r.Date,
+			display.FormatNumber(r.Sessions),
+			display.FormatNumber(r.Messages),
+			display.FormatTokens(r.OutputTokens),
+			display.Dim(formatBytes(r.Bytes)))
+	}
+	fmt.Printf("\n  %s\n\n", display.Dim(store.SnapshotDir()))
+	return nil
+}
+
+func snapshotsDiff(refA, refB string) error {
+	a, err := store.LoadSnapshot(refA)
+	if err != nil {
+		return err
+	}
+	b, err := store.LoadSnapshot(refB)
+	if err != nil {
+		return err
+	}
+	d := store.DiffSnapshots(a, b)
+
+	removed := make([]string, 0, len(d.Removed))
+	for id := range d.Removed {
+		removed = append(removed, id)
+	}
+	sort.Strings(removed)
+	days := make([]string, 0, len(d.ShrunkDays))
+	for date := range d.ShrunkDays {
+		days = append(days, date)
+	}
+	sort.Strings(days)
+	models := make([]string, 0, len(d.OutputTokens))
+	for m := range d.OutputTokens {
+		models = append(models, m)
+	}
+	sort.Strings(models)
+
+	if display.IsJSON() {
+		removedOut := make([]map[string]string, 0, len(removed))
+		for _, id := range removed {
+			removedOut = append(removedOut, map[string]string{"sessionId": id, "project": d.Removed[id]})
+		}
+		shrunk := make([]map[string]any, 0, len(days))
+		for _, date := range days {
+			shrunk = append(shrunk, map[string]any{
+				"date": date, "before": d.ShrunkDays[date][0], "after": d.ShrunkDays[date][1],
+			})
+		}
+		return OutputJSON(map[string]any{
+			"from":         a.Date,
+			"to":           b.Date,
+			"sessions":     d.Sessions,
+			"messages":     d.Messages,
+			"outputTokens": d.OutputTokens,
+			"added":        d.Added,
+			"removed":      removedOut,
+			"shrunkDays":   shrunk,
+		})
+	}
+
+	if display.IsMD() {
+		MDHeader(1, fmt.Sprintf("Snapshots %s → %s", a.Date, b.Date))
+		MDTable([]string{"Metric", a.Date, b.Date, "Change"}, [][]string{
+			{"Sessions", display.FormatNumber(a.Stats.TotalSessions), display.FormatNumber(b.Stats.TotalSessions), signedNumber(d.Sessions)},
+			{"Messages", display.FormatNumber(a.Stats.TotalMessages), display.FormatNumber(b.Stats.TotalMessages), signedNumber(d.Messages)},
+		})
+		if len(models) > 0 {
+			MDHeader(2, "Output Tokens")
+			var rows [][]string
+			for _, m := range models {
+				rows = append(rows, []string{display.ModelShort(m), signedTokens(d.OutputTokens[m])})
+			}
+			MDTable([]string{"Model", "Change"}, rows)
+		}
+		if len(removed) > 0 {
+			MDHeader(2, "Removed Sessions")
+			var rows [][]string
+			for _, id := range removed {
+				rows = append(rows, []string{id, d.Removed[id]})
+			}
+			MDTable([]string{"Session", "Project"}, rows)
+		}
+		if len(days) > 0 {
+			MDHeader(2, "Days With Fewer Messages")
+			var rows [][]string
+			for _, date := range days {
+				rows = append(rows, []string{date,
+					display.FormatNumber(d.ShrunkDays[date][0]),
+					display.FormatNumber(d.ShrunkDays[date][1])})
+			}
+			MDTable([]string{"Date", a.Date, b.Date}, rows)
+		}
+		return nil
+	}
+
+	fmt.Printf("\n%s  %s → %s\n\n", display.BoldCyan("Snapshots"), display.Bold(a.Date), display.Bold(b.Date))
+
+	display.Box("Totals", func() {
+		fmt.Printf("  %-14s %10s  %10s  %s\n", "", display.Dim(a.Date), display.Dim(b.Date), "")
+		fmt.Printf("  %-14s %10s  %10s  %s\n", "Sessions",
+			display.FormatNumber(a.Stats.TotalSessions), display.FormatNumber(b.Stats.TotalSessions), signedNumber(d.Sessions))
+		fmt.Printf("  %-14s %10s  %10s  %s\n", "Messages",
+			display.FormatNumber(a.Stats.TotalMessages), display.FormatNumber(b.Stats.TotalMessages), signedNumber(d.Messages))
+		for _, m := range models {
+			fmt.Printf("  %-14s %24s  %s\n", display.Truncate(display.ModelShort(m), 14), display.Dim("output"), signedTokens(d.OutputTokens[m]))
+		}
+	})
+	fmt.Println()
+
+	display.Box("Sessions", func() {
+		fmt.Printf("  New       %s\n", display.Bold(display.FormatNumber(len(d.Added))))
+		fmt.Printf("  Removed   %s\n", display.Bold(display.FormatNumber(len(removed))))
+		for i, id := range removed {
+			if i == snapshotRemovedLimit {
+				fmt.Printf("  %s\n", display.Dim(fmt.Sprintf("… and %d more", len(removed)-i)))
+				break
+			}
+			fmt.Printf("  %s %s  %s\n", display.Red("-"), id, display.Dim(d.Removed[id]))
+		}
+	})
+	fmt.Println()
+
+	if len(days) > 0 {
+		display.Box("Days With Fewer Messages", func() {
+			for _, date := range days {
+				before, after := d.ShrunkDays[date][0], d.ShrunkDays[date][1]
+				fmt.Printf("  %s  %8s → %-8s %s\n", date,
+					display.FormatNumber(before), display.FormatNumber(after), display.Red(signedNumber(after-before)))
+			}
+		})
+		fmt.Println()
+	}
+	return nil
+}
+
+func signedNumber(n int) string {
+	if n > 0 {
+		return "+" + display.FormatNumber(n)
+	}
+	if n < 0 {
+		return "-" + display.FormatNumber(-n)
+	}
+	return "±0"
+}
+
+func signedTokens(n int) string {
+	if n > 0 {
+		return "+" + display.FormatTokens(n)
+	}
+	if n < 0 {
+		return "-" + display.FormatTokens(-n)
+	}
+	return "±0"
+}
+
+func snapshotOutputTokens(snap *store.Snapshot) int {
+	total := 0
+	for _, mu := range snap.Stats.ModelUsage {
+		total += mu.OutputTokens
+	}
+	return total
+}
@@ -69,6 +69,12 @@ func Summary() error {
 	})
 	fmt.Println()
 
+	// Streaks and goals
+	current, longest := stats.DailyMessages().Streaks(time.Now())
+	goals, goalsErr := loadGoalStatus(stats, time.Now())
+	printStreaksAndGoals(current, longest, goals, goalsErr)
+	fmt.Println()
+
 	// Model usage
 	display.Box("Models", func() {
 		type modelEntry struct {
@@ -107,7 +113,7 @@ func Summary() error {
 			return hours[i].count > hours[j].count
 		})
 		// Show top 5
-		limit := 5
+		limit := Settings.PeakHours
 		if len(hours) < limit {
 			limit = len(hours)
 		}
@@ -123,7 +129,12 @@ func Summary() error {
 	if stats.LongestSession.SessionID != "" {
 		display.Box("Longest Session", func() {
 			fmt.Printf("  ID        %s\n", display.Dim(stats.LongestSession.SessionID[:8]))
-			fmt.Printf("  Duration  %s\n", display.Bold(display.FormatDuration(stats.LongestSession.Duration)))
+			if stats.LongestSession.ActiveDuration > 0 {
+				fmt.Printf("  Active    %s\n", display.Bold(display.FormatDuration(stats.LongestSession.ActiveDuration)))
+				fmt.Printf("  Wall      %s\n", display.FormatDuration(stats.LongestSession.Duration))
+			} else {
+				fmt.Printf("  Duration  %s\n", display.Bold(display.FormatDuration(stats.LongestSession.Duration)))
+			}
 			fmt.Printf("  Messages  %s\n", display.Bold(display.FormatNumber(stats.LongestSession.MessageCount)))
 		})
 		fmt.Println()
@@ -193,11 +204,20 @@ func summaryJSON(stats *store.StatsCache) error {
 	}
 
 	if stats.LongestSession.SessionID != "" {
-		data["longestSession"] = map[string]any{
-			"sessionId": stats.LongestSession.SessionID,
-			"duration":  display.FormatDuration(stats.LongestSession.Duration),
-			"messages":  stats.LongestSession.MessageCount,
-		}
+		data["longestSession"] = longestSessionJSON(stats.LongestSession)
+	}
+
+	current, longest := stats.DailyMessages().Streaks(time.Now())
+	data["streaks"] = map[string]int{
+		"current": current,
+		"longest": longest,
+	}
+	goals, err := loadGoalStatus(stats, time.Now())
+	if err != nil {
+		data["goalsError"] = err.Error()
+	}
+	if goals != nil {
+		data["goals"] = goals
 	}
 
 	return OutputJSON(data)
@@ -237,6 +257,30 @@ func summaryMD(stats *store.StatsCache) error {
 		fmt.Println()
 	}
 
+	current, longest := stats.DailyMessages().Streaks(time.Now())
+	MDHeader(3, "Streaks & Goals")
+	fmt.Printf("- **Current streak:** %s\n", pluralDays(current))
+	fmt.Printf("- **Longest streak:** %s\n\n", pluralDays(longest))
+	goals, err := loadGoalStatus(stats, time.Now())
+	if err != nil {
+		fmt.Printf("> ⚠ %v\n\n", err)
+	}
+	if len(goals) > 0 {
+		gHeaders := []string{"Goal", "Period", "Value", "Target", "Status", "Previous"}
+		var gRows [][]string
+		for _, g := range goals {
+			gRows = append(gRows, []string{
+				g.Name,
+				g.Period,
+				g.format(g.Value),
+				g.target(),
+				g.Status,
+				goalMarks(g.History),
+			})
+		}
+		MDTable(gHeaders, gRows)
+	}
+
 	MDHeader(3, "Models")
 	headers := []string{"Model", "Output", "Cache Read"}
 	var rows [][]string
@@ -274,7 +318,7 @@ func summaryMD(stats *store.StatsCache) error {
 	sort.Slice(hours, func(i, j int) bool {
 		return hours[i].count > hours[j].count
 	})
-	limit := 5
+	limit := Settings.PeakHours
 	if len(hours) < limit {
 		limit = len(hours)
 	}
@@ -292,13 +336,32 @@ func summaryMD(stats *store.StatsCache) error {
 	if stats.LongestSession.SessionID != "" {
 		MDHeader(3, "Longest Session")
 		fmt.Printf("- **ID:** %s\n", stats.LongestSession.SessionID[:8])
-		fmt.Printf("- **Duration:** %s\n", display.FormatDuration(stats.LongestSession.Duration))
+		if stats.LongestSession.ActiveDuration > 0 {
+			fmt.Printf("- **Active:** %s\n", display.FormatDuration(stats.LongestSession.ActiveDuration))
+			fmt.Printf("- **Wall:** %s\n", display.FormatDuration(stats.LongestSession.Duration))
+		} else {
+			fmt.Printf("- **Duration:** %s\n", display.FormatDuration(stats.LongestSession.Duration))
+		}
 		fmt.Printf("- **Messages:** %s\n\n", display.FormatNumber(stats.LongestSession.MessageCount))
 	}
 
 	return nil
 }
 
+// longestSessionJSON reports wall-clock duration, plus active time when the
+// cache was computed by ccs
+func longestSessionJSON(ls store.LongestSession) map[string]any {
+	out := map[string]any{
+		"sessionId": ls.SessionID,
+		"duration":  display.FormatDuration(ls.Duration),
+		"messages":  ls.MessageCount,
+	}
+	if ls.ActiveDuration > 0 {
+		out["activeDuration"] = display.FormatDuration(ls.ActiveDuration)
+	}
+	return out
+}
+
 func findDay(days []store.DailyActivity, date string) *store.DailyActivity {
 	for i := range days {
 		if days[i].Date == date {
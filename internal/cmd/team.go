@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// teamMember holds one user's totals for the comparison table
+type teamMember struct {
+	User       string
+	Sessions   int
+	Messages   int
+	TokensOut  int
+	CacheRead  int
+	ActiveDays int
+	LastActive string
+}
+
+func teamFlags(fs *flag.FlagSet) Runner {
+	days := fs.Int("days", 14, "`N` days in the daily trend")
+	return func(args []string) error {
+		if len(args) != 1 {
+			return usageError("team")
+		}
+		if *days <= 0 {
+			return fmt.Errorf("invalid --days %d", *days)
+		}
+		return Team(args[0], *days)
+	}
+}
+
+// Team compares the exported bundles in dir
+func Team(dir string, days int) error {
+	bundles, err := store.LoadBundles(dir)
+	if err != nil {
+		return fmt.Errorf("loading bundles: %w", err)
+	}
+	if len(bundles) == 0 {
+		return fmt.Errorf("no bundles found in %s", dir)
+	}
+
+	var caches []*store.StatsCache
+	var members []teamMember
+	for i := range bundles {
+		b := &bundles[i]
+		caches = append(caches, &b.Stats)
+
+		m := teamMember{
+			User:       b.User,
+			Sessions:   b.Stats.TotalSessions,
+			Messages:   b.Stats.TotalMessages,
+			ActiveDays: len(b.Stats.DailyActivity),
+		}
+		for _, mu := range b.Stats.ModelUsage {
+			m.TokensOut += mu.OutputTokens
+			m.CacheRead += mu.CacheReadInputTokens
+		}
+		if n := len(b.Stats.DailyActivity); n > 0 {
+			m.LastActive = b.Stats.DailyActivity[n-1].Date
+		}
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Messages > members[j].Messages
+	})
+
+	merged := store.MergeStats(caches)
+
+	if display.IsJSON() {
+		return teamJSON(merged, members, days)
+	}
+	if display.IsMD() {
+		return teamMD(merged, members, days)
+	}
+
+	fmt.Println(display.BoldCyan("Team Report"))
+	fmt.Printf("%d members, tracking since %s\n\n", len(members), dateOnly(merged.FirstSessionDate))
+
+	display.Box("Combined", func() {
+		var totalOut, totalCache int
+		for _, m := range members {
+			totalOut += m.TokensOut
+			totalCache += m.CacheRead
+		}
+		fmt.Printf("  Sessions    %s\n", display.Bold(display.FormatNumber(merged.TotalSessions)))
+		fmt.Printf("  Messages    %s\n", display.Bold(display.FormatNumber(merged.TotalMessages)))
+		fmt.Printf("  Tokens out  %s\n", display.Bold(display.FormatTokens(totalOut)))
+		fmt.Printf("  Cache read  %s\n", display.Bold(display.FormatTokens(totalCache)))
+	})
+	fmt.Println()
+
+	display.Box("Leaderboard", func() {
+		maxMsgs := members[0].Messages
+		for i, m := range members {
+			bar := display.Bar(m.Messages, maxMsgs, 15)
+			fmt.Printf("  %2d. %s %-12s  %3d sessions  %8s out  %3d days  %s\n",
+				i+1,
+				bar,
+				display.FormatNumber(m.Messages)+" msgs",
+				m.Sessions,
+				display.FormatTokens(m.TokensOut),
+				m.ActiveDays,
+				display.Bold(m.User))
+		}
+	})
+	fmt.Println()
+
+	mix := teamModelMix(merged)
+	if len(mix) > 0 {
+		display.Box("Model Mix (output tokens)", func() {
+			for _, m := range mix {
+				bar := display.Bar(m.tokens, mix[0].tokens, 20)
+				fmt.Printf("  %-14s  %s %s  %s\n",
+					display.ModelShort(m.name),
+					bar,
+					display.FormatTokens(m.tokens),
+					display.Dim(fmt.Sprintf("%.1f%%", m.share)))
+			}
+		})
+		fmt.Println()
+	}
+
+	trend := recentDays(merged, days)
+	if len(trend) > 0 {
+		display.Box(fmt.Sprintf("Daily Trend (last %d days)", days), func() {
+			maxMsgs := 0
+			for _, d := range trend {
+				if d.Messages > maxMsgs {
+					maxMsgs = d.Messages
+				}
+			}
+			for _, d := range trend {
+				bar := display.Bar(d.Messages, maxMsgs, 20)
+				fmt.Printf("  %s  %s %s msgs, %s out\n",
+					d.Date,
+					bar,
+					display.FormatNumber(d.Messages),
+					display.FormatTokens(d.TokensOut))
+			}
+		})
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func teamExportFlags(fs *flag.FlagSet) Runner {
+	user := fs.String("user", os.Getenv("USER"), "User `NAME` for export (default: $USER)")
+	out := fs.String("o", "", "Export `FILE` (default: <user>.json)")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("team export")
+		}
+		return teamExport(*user, *out)
+	}
+}
+
+func teamExport(user, out string) error {
+	if user == "" {
+		user = "anonymous"
+	}
+	if out == "" {
+		out = user + ".json"
+	}
+
+	stats, err := store.LoadStatsCache()
+	if err != nil {
+		return fmt.Errorf("loading stats cache: %w", err)
+	}
+
+	if err := store.SaveBundle(store.NewBundle(user, stats), out); err != nil {
+		return err
+	}
+	fmt.Printf("Exported bundle for %s to %s\n", display.Bold(user), out)
+	return nil
+}
+
+type teamModel struct {
+	name   string
+	tokens int
+	share  float64
+}
+
+// teamModelMix returns models sorted by output tokens with their share
+func teamModelMix(stats *store.StatsCache) []teamModel {
+	total := 0
+	for _, mu := range stats.ModelUsage {
+		total += mu.OutputTokens
+	}
+	var mix []teamModel
+	for name, mu := range stats.ModelUsage {
+		share := 0.0
+		if total > 0 {
+			share = float64(mu.OutputTokens) * 100 / float64(total)
+		}
+		mix = append(mix, teamModel{name, mu.OutputTokens, share})
+	}
+	sort.Slice(mix, func(i, j int) bool {
+		return mix[i].tokens > mix[j].tokens
+	})
+	return mix
+}
+
+type teamDay struct {
+	Date      string `json:"date"`
+	Sessions  int    `json:"sessions"`
+	Messages  int    `json:"messages"`
+	TokensOut int    `json:"outputTokens"`
+}
+
+// recentDays joins daily activity and output tokens for the last n days of data
+func recentDays(stats *store.StatsCache, n int) []teamDay {
+	tokens := make(map[string]int)
+	for _, d := range stats.DailyModelTokens {
+		for _, t := range d.TokensByModel {
+			tokens[d.Date] += t
+		}
+	}
+
+	days := stats.DailyActivity
+	start := 0
+	if len(days) > n {
+		start = len(days) - n
+	}
+	var out []teamDay
+	for _, d := range days[start:] {
+		out = append(out, teamDay{
+			Date:      d.Date,
+			Sessions:  d.SessionCount,
+			Messages:  d.MessageCount,
+			TokensOut: tokens[d.Date],
+		})
+	}
+	return out
+}
+
+func teamJSON(merged *store.StatsCache, members []teamMember, days int) error {
+	type jsonMember struct {
+		User       string `json:"user"`
+		Sessions   int    `json:"sessions"`
+		Messages   int    `json:"messages"`
+		TokensOut  int    `json:"outputTokens"`
+		CacheRead  int    `json:"cacheReadTokens"`
+		ActiveDays int    `json:"activeDays"`
+		LastActive string `json:"lastActive,omitempty"`
+	}
+	var jsonMembers []jsonMember
+	var totalOut, totalCache int
+	for _, m := range members {
+		jsonMembers = append(jsonMembers, jsonMember(m))
+		totalOut += m.TokensOut
+		totalCache += m.CacheRead
+	}
+
+	models := make(map[string]any)
+	for _, m := range teamModelMix(merged) {
+		models[display.ModelShort(m.name)] = map[string]any{
+			"outputTokens": m.tokens,
+			"share":        m.share,
+		}
+	}
+
+	data := map[string]any{
+		"combined": map[string]any{
+			"members":         len(members),
+			"sessions":        merged.TotalSessions,
+			"messages":        merged.TotalMessages,
+			"outputTokens":    totalOut,
+			"cacheReadTokens": totalCache,
+			"trackingSince":   merged.FirstSessionDate,
+		},
+		"members": jsonMembers,
+		"models":  models,
+		"daily":   recentDays(merged, days),
+	}
+	return OutputJSON(data)
+}
+
+func teamMD(merged *store.StatsCache, members []teamMember, days int) error {
+	MDHeader(2, "Team Report")
+	fmt.Printf("%d members, tracking since %s\n\n", len(members), dateOnly(merged.FirstSessionDate))
+
+	var totalOut, totalCache int
+	for _, m := range members {
+		totalOut += m.TokensOut
+		totalCache += m.CacheRead
+	}
+
+	MDHeader(3, "Combined")
+	fmt.Printf("- **Sessions:** %s\n", display.FormatNumber(merged.TotalSessions))
+	fmt.Printf("- **Messages:** %s\n", display.FormatNumber(merged.TotalMessages))
+	fmt.Printf("- **Tokens out:** %s\n", display.FormatTokens(totalOut))
+	fmt.Printf("- **Cache read:** %s\n\n", display.FormatTokens(totalCache))
+
+	MDHeader(3, "Leaderboard")
+	headers := []string{"#", "User", "Sessions", "Messages", "Tokens Out", "Active Days", "Last Active"}
+	var rows [][]string
+	for i, m := range members {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", i+1),
+			m.User,
+			fmt.Sprintf("%d", m.Sessions),
+			display.FormatNumber(m.Messages),
+			display.FormatTokens(m.TokensOut),
+			fmt.Sprintf("%d", m.ActiveDays),
+			m.LastActive,
+		})
+	}
+	MDTable(headers, rows)
+
+	MDHeader(3, "Model Mix")
+	mHeaders := []string{"Model", "Output Tokens", "Share"}
+	var mRows [][]string
+	for _, m := range teamModelMix(merged) {
+		mRows = append(mRows, []string{
+			display.ModelShort(m.name),
+			display.FormatTokens(m.tokens),
+			fmt.Sprintf("%.1f%%", m.share),
+		})
+	}
+	MDTable(mHeaders, mRows)
+
+	MDHeader(3, fmt.Sprintf("Daily Trend (last %d days)", days))
+	dHeaders := []string{"Date", "Sessions", "Messages", "Output Tokens"}
+	var dRows [][]string
+	for _, d := range recentDays(merged, days) {
+		dRows = append(dRows, []string{
+			d.Date,
+			fmt.Sprintf("%d", d.Sessions),
+			display.FormatNumber(d.Messages),
+			display.FormatTokens(d.TokensOut),
+		})
+	}
+	MDTable(dHeaders, dRows)
+
+	return nil
+}
+
+// dateOnly trims an RFC3339 timestamp down to its date part
+func dateOnly(s string) string {
+	if len(s) >= 10 {
+		return s[:10]
+	}
+	return s
+}
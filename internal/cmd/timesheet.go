@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"crypto/sha1"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+func timesheetFlags(fs *flag.FlagSet) Runner {
+	sinceArg := fs.String("since", "", "First day, `YYYY-MM-DD` (default: start of month)")
+	untilArg := fs.String("until", "", "Last day, `YYYY-MM-DD` (default: today)")
+	project := fs.String("project", "", "Only projects matching `X`")
+	csvOut := fs.Bool("csv", false, "Output as CSV")
+	icsOut := fs.Bool("ics", false, "Output as iCalendar, one event per work block")
+	return func(args []string) error {
+		if len(args) > 0 {
+			return usageError("timesheet")
+		}
+		now := time.Now().In(Settings.Location())
+		since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		until := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		if *sinceArg != "" {
+			t, err := time.ParseInLocation("2006-01-02", *sinceArg, now.Location())
+			if err != nil {
+				return fmt.Errorf("invalid --since date (want YYYY-MM-DD): %w", err)
+			}
+			since = t
+		}
+		if *untilArg != "" {
+			t, err := time.ParseInLocation("2006-01-02", *untilArg, now.Location())
+			if err != nil {
+				return fmt.Errorf("invalid --until date (want YYYY-MM-DD): %w", err)
+			}
+			until = t
+		}
+		format := ""
+		switch {
+		case *csvOut && *icsOut:
+			return fmt.Errorf("--csv and --ics can't be combined")
+		case *csvOut:
+			format = "csv"
+		case *icsOut:
+			format = "ics"
+		}
+		return Timesheet(since, until, *project, format)
+	}
+}
+
+// Timesheet reports active time per project per day from since to until,
+// both inclusive; format is "", "csv" or "ics"
+func Timesheet(since, until time.Time, project, format string) error {
+	now := time.Now().In(Settings.Location())
+	idle := store.IdleGap
+
+	// --until is inclusive
+	end := until.AddDate(0, 0, 1)
+	blocks, err := store.ComputeWorkBlocks(since, end, idle, project)
+	if err != nil {
+		return fmt.Errorf("computing work blocks: %w", err)
+	}
+	days := store.TimesheetDays(blocks, now.Location())
+
+	sinceStr := since.Format("2006-01-02")
+	untilStr := until.Format("2006-01-02")
+
+	switch {
+	case format == "csv":
+		return timesheetCSV(days)
+	case format == "ics":
+		return timesheetICS(blocks)
+	case display.IsJSON():
+		return timesheetJSON(sinceStr, untilStr, idle, days, blocks)
+	case display.IsMD():
+		return timesheetMD(sinceStr, untilStr, idle, days)
+	}
+
+	fmt.Println(display.BoldCyan("Timesheet"))
+	fmt.Printf("%s to %s (idle gap: %s)\n\n", sinceStr, untilStr, display.FormatDurationFromTime(idle))
+
+	if len(days) == 0 {
+		fmt.Println(display.Dim("No activity in this range"))
+		return nil
+	}
+
+	totals := projectTotals(days)
+	var grand time.Duration
+	for _, t := range totals {
+		grand += t.Duration
+	}
+
+	display.Box("Projects", func() {
+		for _, t := range totals {
+			bar := display.Bar(int(t.Duration.Minutes()), int(totals[0].Duration.Minutes()), 15)
+			fmt.Printf("  %s %8s  %s\n",
+				bar,
+				display.FormatDurationFromTime(t.Duration),
+				display.Bold(t.Project))
+		}
+		fmt.Printf("  %s %8s\n", strings.Repeat(" ", 15), display.Bold(display.FormatDurationFromTime(grand)))
+	})
+	fmt.Println()
+
+	display.Box("Daily", func() {
+		lastDate := ""
+		for _, d := range days {
+			date := d.Date
+			if date == lastDate {
+				date = strings.Repeat(" ", len(date))
+			}
+			lastDate = d.Date
+			fmt.Printf("  %s  %8s  %s\n",
+				date,
+				display.FormatDurationFromTime(d.Duration),
+				d.Project)
+		}
+	})
+	fmt.Println()
+
+	return nil
+}
+
+// projectTotals sums timesheet days per project, longest first
+func projectTotals(days []store.TimesheetDay) []store.TimesheetDay {
+	byProject := make(map[string]time.Duration)
+	for _, d := range days {
+		byProject[d.Project] += d.Duration
+	}
+	var totals []store.TimesheetDay
+	for p, d := range byProject {
+		totals = append(totals, store.TimesheetDay{Project: p, Duration: d})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Duration > totals[j].Duration
+	})
+	return totals
+}
+
+// hours formats a duration as decimal hours for billing
+func hours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
+
+func timesheetCSV(days []store.TimesheetDay) error {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"date", "project", "minutes", "hours"})
+	for _, d := range days {
+		w.Write([]string{
+			d.Date,
+			d.Project,
+			fmt.Sprintf("%d", int(d.Duration.Round(time.Minute).Minutes())),
+			hours(d.Duration),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func timesheetICS(blocks []store.WorkBlock) error {
+	const stamp = "20060102T150405Z"
+	now := time.Now().UTC().Format(stamp)
+
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(icsFold(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//ccs//Claude Code Summary//EN")
+	line("CALSCALE:GREGORIAN")
+	for _, blk := range blocks {
+		// A single message has no measurable duration
+		if blk.Duration() <= 0 {
+			continue
+		}
+		uid := fmt.Sprintf("%x", sha1.Sum([]byte(blk.Project+blk.Start.String())))
+		line("BEGIN:VEVENT")
+		line("UID:" + uid[:16] + "@ccs")
+		line("DTSTAMP:" + now)
+		line("DTSTART:" + blk.Start.UTC().Format(stamp))
+		line("DTEND:" + blk.End.UTC().Format(stamp))
+		line("SUMMARY:" + icsEscape(blk.Project))
+		line("DESCRIPTION:" + icsEscape(fmt.Sprintf("%s active, %d sessions", display.FormatDurationFromTime(blk.Duration()), blk.Sessions)))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	_, err := fmt.Print(b.String())
+	return err
+}
+
+// icsFold folds a content line longer than 75 octets into continuation
+// lines starting with a space (RFC 5545 section 3.1), without splitting a
+// UTF-8 character
+func icsFold(s string) string {
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		n := limit
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		b.WriteString(s[:n])
+		b.WriteString("\r\n ")
+		s = s[n:]
+		// The leading space counts towards the next line's 75 octets
+		limit = 74
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// icsEscape escapes text values per RFC 5545
+func icsEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+func timesheetJSON(since, until string, idle time.Duration, days []store.TimesheetDay, blocks []store.WorkBlock) error {
+	type jsonDay struct {
+		Date    string  `json:"date"`
+		Project string  `json:"project"`
+		Minutes int     `json:"minutes"`
+		Hours   float64 `json:"hours"`
+	}
+	type jsonBlock struct {
+		Project  string `json:"project"`
+		Start    string `json:"start"`
+		End      string `json:"end"`
+		Minutes  int    `json:"minutes"`
+		Sessions int    `json:"sessions"`
+	}
+
+	var jsonDays []jsonDay
+	for _, d := range days {
+		jsonDays = append(jsonDays, jsonDay{
+			Date:    d.Date,
+			Project: d.Project,
+			Minutes: int(d.Duration.Round(time.Minute).Minutes()),
+			Hours:   d.Duration.Hours(),
+		})
+	}
+	var jsonBlocks []jsonBlock
+	for _, b := range blocks {
+		jsonBlocks = append(jsonBlocks, jsonBlock{
+			Project:  b.Project,
+			Start:    b.Start.Format(time.RFC3339),
+			End:      b.End.Format(time.RFC3339),
+			Minutes:  int(b.Duration().Round(time.Minute).Minutes()),
+			Sessions: b.Sessions,
+		})
+	}
+	totals := make(map[string]float64)
+	for _, t := range projectTotals(days) {
+		totals[t.Project] = t.Duration.Hours()
+	}
+
+	data := map[string]any{
+		"since":        since,
+		"until":        until,
+		"idleMinutes":  int(idle.Minutes()),
+		"projectHours": totals,
+		"days":         jsonDays,
+		"blocks":       jsonBlocks,
+	}
+	return OutputJSON(data)
+}
+
+func timesheetMD(since, until string, idle time.Duration, days []store.TimesheetDay) error {
+	MDHeader(2, "Timesheet")
+	fmt.Printf("%s to %s (idle gap: %s)\n\n", since, until, display.FormatDurationFromTime(idle))
+
+	if len(days) == 0 {
+		fmt.Println("No activity in this range.")
+		fmt.Println()
+		return nil
+	}
+
+	MDHeader(3, "Projects")
+	pHeaders := []string{"Project", "Time", "Hours"}
+	var pRows [][]string
+	var grand time.Duration
+	for _, t := range projectTotals(days) {
+		grand += t.Duration
+		pRows = append(pRows, []string{
+			t.Project,
+			display.FormatDurationFromTime(t.Duration),
+			hours(t.Duration),
+		})
+	}
+	pRows = append(pRows, []string{"**Total**", display.FormatDurationFromTime(grand), hours(grand)})
+	MDTable(pHeaders, pRows)
+
+	MDHeader(3, "Daily")
+	dHeaders := []string{"Date", "Project", "Time", "Hours"}
+	var dRows [][]string
+	for _, d := range days {
+		dRows = append(dRows, []string{
+			d.Date,
+			d.Project,
+			display.FormatDurationFromTime(d.Duration),
+			hours(d.Duration),
+		})
+	}
+	MDTable(dHeaders, dRows)
+
+	return nil
+}
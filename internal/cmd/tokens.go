@@ -49,12 +49,12 @@ func Tokens() error {
 	})
 	fmt.Println()
 
-	// Daily output tokens (last 14 days)
-	display.Box("Daily Output Tokens (last 14 days)", func() {
+	// Daily output tokens (last N days)
+	display.Box(fmt.Sprintf("Daily Output Tokens (last %d days)", Settings.TokensDays), func() {
 		days := stats.DailyModelTokens
 		start := 0
-		if len(days) > 14 {
-			start = len(days) - 14
+		if len(days) > Settings.TokensDays {
+			start = len(days) - Settings.TokensDays
 		}
 		recent := days[start:]
 
@@ -101,8 +101,8 @@ func tokensJSON(stats *store.StatsCache) error {
 	}
 	days := stats.DailyModelTokens
 	start := 0
-	if len(days) > 14 {
-		start = len(days) - 14
+	if len(days) > Settings.TokensDays {
+		start = len(days) - Settings.TokensDays
 	}
 	recent := days[start:]
 	var dailyTokens []dailyEntry
@@ -153,11 +153,11 @@ func tokensMD(stats *store.StatsCache) error {
 	}
 	MDTable(headers, rows)
 
-	MDHeader(3, "Daily Output Tokens (last 14 days)")
+	MDHeader(3, fmt.Sprintf("Daily Output Tokens (last %d days)", Settings.TokensDays))
 	days := stats.DailyModelTokens
 	start := 0
-	if len(days) > 14 {
-		start = len(days) - 14
+	if len(days) > Settings.TokensDays {
+		start = len(days) - Settings.TokensDays
 	}
 	recent := days[start:]
 
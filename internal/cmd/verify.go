@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// verifyModel compares raw and de-duplicated token totals for one model
+type verifyModel struct {
+	name    string
+	counted store.ModelUsage
+	removed store.ModelUsage
+}
+
+func Verify() error {
+	stats, report, err := store.VerifyStats(func(done, total int) {
+		if !display.IsJSON() && !display.IsMD() {
+			fmt.Fprintf(os.Stderr, "\r  Scanning... %d/%d sessions", done, total)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("computing stats: %w", err)
+	}
+	if !display.IsJSON() && !display.IsMD() {
+		fmt.Fprintln(os.Stderr)
+	}
+
+	models := verifyModels(stats, report)
+
+	type sessionEntry struct {
+		id     string
+		tokens int
+	}
+	var sessions []sessionEntry
+	for id, tokens := range report.RemovedBySession {
+		sessions = append(sessions, sessionEntry{id, tokens})
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].tokens > sessions[j].tokens
+	})
+	if len(sessions) > 10 {
+		sessions = sessions[:10]
+	}
+
+	if display.IsJSON() {
+		jsonModels := make(map[string]any)
+		for _, m := range models {
+			jsonModels[display.ModelShort(m.name)] = map[string]any{
+				"counted": usageMap(m.counted),
+				"removed": usageMap(m.removed),
+				"raw":     usageMap(addUsage(m.counted, m.removed)),
+			}
+		}
+		jsonSessions := make([]map[string]any, 0, len(sessions))
+		for _, s := range sessions {
+			jsonSessions = append(jsonSessions, map[string]any{
+				"sessionId":     s.id,
+				"removedTokens": s.tokens,
+			})
+		}
+		return OutputJSON(map[string]any{
+			"lines":          report.Lines,
+			"duplicateLines": report.DuplicateLines,
+			"usageEntries":   report.UsageEntries,
+			"duplicateUsage": report.DuplicateUsage,
+			"crossFileUsage": report.CrossFileUsage,
+			"models":         jsonModels,
+			"sessions":       jsonSessions,
+		})
+	}
+
+	if display.IsMD() {
+		MDHeader(2, "Token Accounting")
+		fmt.Printf("Scanned %s sessions, %s lines\n\n",
+			display.FormatNumber(stats.TotalSessions), display.FormatNumber(report.Lines))
+
+		MDHeader(3, "Duplicates Removed")
+		fmt.Printf("- **Streamed repeats:** %s usage entries\n", display.FormatNumber(report.DuplicateUsage))
+		fmt.Printf("- **Copied history:** %s lines, %s usage entries\n\n",
+			display.FormatNumber(report.DuplicateLines), display.FormatNumber(report.CrossFileUsage))
+
+		MDHeader(3, "Tokens by Model")
+		headers := []string{"Model", "Type", "Raw", "Counted", "Removed"}
+		var rows [][]string
+		for _, m := range models {
+			for _, r := range verifyRows(m) {
+				rows = append(rows, []string{
+					display.ModelShort(m.name),
+					r.label,
+					display.FormatTokens(r.raw),
+					display.FormatTokens(r.counted),
+					fmt.Sprintf("%s (%s)", display.FormatTokens(r.raw-r.counted), percent(r.raw-r.counted, r.raw)),
+				})
+			}
+		}
+		MDTable(headers, rows)
+
+		if len(sessions) > 0 {
+			MDHeader(3, "Most Affected Sessions")
+			sHeaders := []string{"ID", "Removed Tokens"}
+			var sRows [][]string
+			for _, s := range sessions {
+				sRows = append(sRows, []string{s.id[:8], display.FormatTokens(s.tokens)})
+			}
+			MDTable(sHeaders, sRows)
+		}
+		return nil
+	}
+
+	fmt.Println(display.BoldCyan("Token Accounting"))
+	fmt.Printf("Scanned %s sessions, %s lines\n\n",
+		display.FormatNumber(stats.TotalSessions), display.FormatNumber(report.Lines))
+
+	display.Box("Duplicates Removed", func() {
+		fmt.Printf("  Streamed repeats  %s usage entries %s\n",
+			display.Bold(display.FormatNumber(report.DuplicateUsage)),
+			display.Dim("(one response split over several lines)"))
+		fmt.Printf("  Copied history    %s lines, %s usage entries %s\n",
+			display.Bold(display.FormatNumber(report.DuplicateLines)),
+			display.Bold(display.FormatNumber(report.CrossFileUsage)),
+			display.Dim("(resumed sessions)"))
+	})
+	fmt.Println()
+
+	display.Box("Tokens by Model", func() {
+		for _, m := range models {
+			fmt.Printf("  %s\n", display.Bold(display.ModelShort(m.name)))
+			for _, r := range verifyRows(m) {
+				fmt.Printf("    %-15s raw %-8s counted %-8s removed %s\n",
+					r.label,
+					display.FormatTokens(r.raw),
+					display.Bold(display.FormatTokens(r.counted)),
+					display.Yellow(fmt.Sprintf("%s (%s)", display.FormatTokens(r.raw-r.counted), percent(r.raw-r.counted, r.raw))))
+			}
+			fmt.Println()
+		}
+	})
+	fmt.Println()
+
+	if len(sessions) > 0 {
+		display.Box("Most Affected Sessions", func() {
+			for _, s := range sessions {
+				fmt.Printf("  %s  %s removed\n", display.Dim(s.id[:8]), display.FormatTokens(s.tokens))
+			}
+		})
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// verifyModels pairs counted and removed usage per model, largest raw output first
+func verifyModels(stats *store.StatsCache, report *store.DedupReport) []verifyModel {
+	byName := make(map[string]*verifyModel)
+	get := func(name string) *verifyModel {
+		if byName[name] == nil {
+			byName[name] = &verifyModel{name: name}
+		}
+		return byName[name]
+	}
+	for name, mu := range stats.ModelUsage {
+		get(name).counted = mu
+	}
+	for name, mu := range report.Removed {
+		get(name).removed = mu
+	}
+
+	var models []verifyModel
+	for _, m := range byName {
+		models = append(models, *m)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].counted.OutputTokens+models[i].removed.OutputTokens >
+			models[j].counted.OutputTokens+models[j].removed.OutputTokens
+	})
+	return models
+}
+
+type verifyRow struct {
+	label   string
+	raw     int
+	counted int
+}
+
+func verifyRows(m verifyModel) []verifyRow {
+	raw := addUsage(m.counted, m.removed)
+	return []verifyRow{
+		{"Input", raw.InputTokens, m.counted.InputTokens},
+		{"Output", raw.OutputTokens, m.counted.OutputTokens},
+		{"Cache read", raw.CacheReadInputTokens, m.counted.CacheReadInputTokens},
+		{"Cache creation", raw.CacheCreationInputTokens, m.counted.CacheCreationInputTokens},
+	}
+}
+
+func addUsage(a, b store.ModelUsage) store.ModelUsage {
+	return store.ModelUsage{
+		InputTokens:              a.InputTokens + b.InputTokens,
+		OutputTokens:             a.OutputTokens + b.OutputTokens,
+		CacheReadInputTokens:     a.CacheReadInputTokens + b.CacheReadInputTokens,
+		CacheCreationInputTokens: a.CacheCreationInputTokens + b.CacheCreationInputTokens,
+	}
+}
+
+func usageMap(m store.ModelUsage) map[string]int {
+	return map[string]int{
+		"inputTokens":   m.InputTokens,
+		"outputTokens":  m.OutputTokens,
+		"cacheRead":     m.CacheReadInputTokens,
+		"cacheCreation": m.CacheCreationInputTokens,
+	}
+}
+
+func percent(part, whole int) string {
+	if whole == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(whole))
+}
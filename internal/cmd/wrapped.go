@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/display"
+	"github.com/dkd/ccs/internal/store"
+)
+
+// wrappedItem is one entry of a top-N list
+type wrappedItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// wrappedHighlight is a labeled fact such as the busiest day
+type wrappedHighlight struct {
+	Label  string `json:"label"`
+	Value  string `json:"value"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// wrappedReport is the year in review, formatted once for every renderer
+type wrappedReport struct {
+	Year        int                `json:"year"`
+	Totals      []wrappedHighlight `json:"totals"`
+	Highlights  []wrappedHighlight `json:"highlights"`
+	Projects    []wrappedItem      `json:"topProjects"`
+	Tools       []wrappedItem      `json:"topTools"`
+	Files       []wrappedItem      `json:"mostEditedFiles"`
+	Hours       []int              `json:"hours"`
+	Personality string             `json:"personality"`
+	Blurb       string             `json:"personalityDetail"`
+}
+
+func wrappedFlags(fs *flag.FlagSet) Runner {
+	var page optionalValue
+	fs.Var(&page, "html", "Write a self-contained HTML page to stdout or `FILE`")
+	return func(args []string) error {
+		year := time.Now().In(Settings.Location()).Year()
+		switch len(args) {
+		case 0:
+		case 1:
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 2000 {
+				return fmt.Errorf("invalid year %q", args[0])
+			}
+			year = n
+		default:
+			return usageError("wrapped")
+		}
+		return Wrapped(year, page.set, page.value)
+	}
+}
+
+func Wrapped(year int, htmlOut bool, htmlPath string) error {
+	fmt.Fprintf(os.Stderr, "  Scanning %d...", year)
+	w, err := store.ComputeWrapped(year, Settings.Location())
+	fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", 20))
+	if err != nil {
+		return fmt.Errorf("loading sessions: %w", err)
+	}
+	if w.Messages == 0 {
+		return fmt.Errorf("no activity in %d", year)
+	}
+
+	r := buildWrapped(w)
+
+	switch {
+	case htmlOut:
+		out := wrappedHTML(r)
+		if htmlPath == "" {
+			fmt.Print(out)
+			return nil
+		}
+		if err := os.WriteFile(htmlPath, []byte(out), 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", htmlPath, err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", htmlPath)
+		return nil
+	case display.IsJSON():
+		return OutputJSON(r)
+	case display.IsMD():
+		wrappedMD(r)
+		return nil
+	}
+
+	fmt.Println(display.BoldMagenta(fmt.Sprintf("✦ Your %d in Claude Code ✦", r.Year)))
+	fmt.Println()
+
+	display.Box("The Year in Numbers", func() {
+		for _, t := range r.Totals {
+			fmt.Printf("  %-14s %s\n", t.Label, display.Bold(t.Value))
+		}
+	})
+	fmt.Println()
+
+	display.Box("Highlights", func() {
+		for _, h := range r.Highlights {
+			detail := ""
+			if h.Detail != "" {
+				detail = "  " + display.Dim(h.Detail)
+			}
+			fmt.Printf("  %-16s %s%s\n", h.Label, display.Bold(h.Value), detail)
+		}
+	})
+	fmt.Println()
+
+	lists := []struct {
+		title string
+		items []wrappedItem
+	}{
+		{"Top Projects (messages)", r.Projects},
+		{"Top Tools", r.Tools},
+		{"Most Edited Files", r.Files},
+	}
+	for _, l := range lists {
+		if len(l.items) == 0 {
+			continue
+		}
+		display.Box(l.title, func() {
+			for i, it := range l.items {
+				fmt.Printf("  %d. %s %s %s\n", i+1,
+					display.Bar(it.Count, l.items[0].Count, 12),
+					display.Bold(fmt.Sprintf("%6s", display.FormatNumber(it.Count))),
+					display.Truncate(it.Name, 60))
+			}
+		})
+		fmt.Println()
+	}
+
+	display.Box("Your Coding Hours", func() {
+		fmt.Printf("  %s\n", display.Cyan(display.Sparkline(r.Hours)))
+		fmt.Printf("  %s\n", display.Dim("0     6     12    18   23"))
+		fmt.Printf("  %s — %s\n", display.BoldYellow(r.Personality), r.Blurb)
+	})
+	fmt.Println()
+
+	return nil
+}
+
+// buildWrapped picks the highlights and top lists from the raw aggregate
+func buildWrapped(w *store.Wrapped) *wrappedReport {
+	r := &wrappedReport{Year: w.Year, Hours: w.Hours[:]}
+
+	total := store.ProjectUsage{Models: w.Models}.Total()
+	r.Totals = []wrappedHighlight{
+		{Label: "Sessions", Value: display.FormatNumber(w.Sessions)},
+		{Label: "Messages", Value: display.FormatNumber(w.Messages)},
+		{Label: "Tool calls", Value: display.FormatNumber(w.ToolCalls)},
+		{Label: "Tokens", Value: display.FormatTokens(total.TotalTokens())},
+		{Label: "Estimated cost", Value: display.FormatCost(store.EstimateCostByModel(w.Models))},
+		{Label: "Active days", Value: display.FormatNumber(w.ActiveDays)},
+	}
+
+	day, dayN := w.Busiest(func(d time.Time) string { return d.Format("2006-01-02") })
+	week, weekN := w.Busiest(func(d time.Time) string {
+		return periodStart("week", d).Format("2006-01-02")
+	})
+	month, monthN := w.Busiest(func(d time.Time) string { return d.Format("2006-01") })
+	if m, err := time.Parse("2006-01", month); err == nil {
+		month = m.Format("January")
+	}
+	if d, err := time.Parse("2006-01-02", day); err == nil {
+		day = d.Format("Mon, Jan 2")
+	}
+	if d, err := time.Parse("2006-01-02", week); err == nil {
+		week = "Week of " + d.Format("Jan 2")
+	}
+	r.Highlights = []wrappedHighlight{
+		{"Busiest day", day, display.FormatNumber(dayN) + " messages"},
+		{"Busiest week", week, display.FormatNumber(weekN) + " messages"},
+		{"Busiest month", month, display.FormatNumber(monthN) + " messages"},
+		{"Longest streak", fmt.Sprintf("%d days", w.StreakDays), w.StreakStart + " – " + w.StreakEnd},
+	}
+
+	fav, favOut := "", -1
+	for model, mu := range w.Models {
+		if mu.OutputTokens > favOut || (mu.OutputTokens == favOut && model < fav) {
+			fav, favOut = model, mu.OutputTokens
+		}
+	}
+	if fav != "" {
+		r.Highlights = append(r.Highlights, wrappedHighlight{
+			"Favorite model", display.ModelShort(fav), display.FormatTokens(favOut) + " output tokens",
+		})
+	}
+	if ls := w.Longest; ls != nil {
+		started := ls.Timestamp
+		if t, err := time.Parse(time.RFC3339, ls.Timestamp); err == nil {
+			started = t.In(Settings.Location()).Format("Jan 2")
+		}
+		r.Highlights = append(r.Highlights, wrappedHighlight{
+			"Longest session", display.FormatDuration(ls.ActiveDuration),
+			fmt.Sprintf("%s, %s, %s", filepath.Base(w.LongestPath), started, ls.SessionID[:8]),
+		})
+	}
+
+	r.Projects = topItems(w.Projects, 5)
+	r.Tools = topItems(w.Tools, 5)
+	r.Files = topItems(w.Files, 5)
+	if home, err := os.UserHomeDir(); err == nil {
+		for i := range r.Files {
+			if strings.HasPrefix(r.Files[i].Name, home+"/") {
+				r.Files[i].Name = "~" + strings.TrimPrefix(r.Files[i].Name, home)
+			}
+		}
+	}
+
+	r.Personality, r.Blurb = hourPersonality(w.Hours)
+	return r
+}
+
+// hourPersonality names the part of the day with the most messages
+func hourPersonality(hours [24]int) (string, string) {
+	parts := []struct {
+		name, blurb string
+		from, to    int // hours, to exclusive, may wrap past midnight
+	}{
+		{"Early Bird", "most of your messages were sent before noon", 5, 12},
+		{"Daytime Coder", "you do your best work in the afternoon", 12, 18},
+		{"Evening Hacker", "your sessions pick up after dinner", 18, 23},
+		{"Night Owl", "you code while everyone else is asleep", 23, 5},
+	}
+	best, bestN := 0, -1
+	for i, p := range parts {
+		n := 0
+		for h := p.from; h != p.to; h = (h + 1) % 24 {
+			n += hours[h]
+		}
+		if n > bestN {
+			best, bestN = i, n
+		}
+	}
+	return parts[best].name, parts[best].blurb
+}
+
+// topItems returns the n largest entries of m, ties by name
+func topItems(m map[string]int, n int) []wrappedItem {
+	var items []wrappedItem
+	for name, count := range m {
+		items = append(items, wrappedItem{name, count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Name < items[j].Name
+	})
+	if len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
+
+func wrappedMD(r *wrappedReport) {
+	MDHeader(2, fmt.Sprintf("Your %d in Claude Code", r.Year))
+
+	MDHeader(3, "The Year in Numbers")
+	for _, t := range r.Totals {
+		fmt.Printf("- **%s:** %s\n", t.Label, t.Value)
+	}
+	fmt.Println()
+
+	MDHeader(3, "Highlights")
+	for _, h := range r.Highlights {
+		if h.Detail != "" {
+			fmt.Printf("- **%s:** %s (%s)\n", h.Label, h.Value, h.Detail)
+		} else {
+			fmt.Printf("- **%s:** %s\n", h.Label, h.Value)
+		}
+	}
+	fmt.Println()
+
+	lists := []struct {
+		title, column string
+		items         []wrappedItem
+	}{
+		{"Top Projects", "Messages", r.Projects},
+		{"Top Tools", "Calls", r.Tools},
+		{"Most Edited Files", "Edits", r.Files},
+	}
+	for _, l := range lists {
+		if len(l.items) == 0 {
+			continue
+		}
+		MDHeader(3, l.title)
+		var rows [][]string
+		for i, it := range l.items {
+			rows = append(rows, []string{strconv.Itoa(i + 1), it.Name, display.FormatNumber(it.Count)})
+		}
+		MDTable([]string{"#", "Name", l.column}, rows)
+	}
+
+	MDHeader(3, "Your Coding Hours")
+	fmt.Printf("`%s`\n\n", display.Sparkline(r.Hours))
+	fmt.Printf("**%s**: %s.\n\n", r.Personality, r.Blurb)
+}
+
+// wrappedHTML renders a self-contained HTML page
+func wrappedHTML(r *wrappedReport) string {
+	esc := html.EscapeString
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Your %d in Claude Code</title>
+<style>
+  body { font-family: -apple-system, Helvetica, Arial, sans-serif; background: #14111f; color: #eee; max-width: 760px; margin: 40px auto; padding: 0 20px; }
+  h1 { color: #d97757; font-size: 2.2em; }
+  h2 { color: #b9a7ff; border-bottom: 1px solid #333; padding-bottom: 4px; }
+  .grid { display: grid; grid-template-columns: repeat(3, 1fr); gap: 12px; }
+  .card { background: #221d33; border-radius: 10px; padding: 14px; }
+  .value { font-size: 1.6em; font-weight: bold; }
+  .label, .detail { color: #999; font-size: 0.9em; }
+  table { width: 100%%; border-collapse: collapse; }
+  td { padding: 4px 6px; border-bottom: 1px solid #2a2440; }
+  td.n { text-align: right; font-variant-numeric: tabular-nums; }
+  .hours { display: flex; align-items: flex-end; height: 80px; gap: 2px; }
+  .hours div { flex: 1; background: #d97757; border-radius: 2px 2px 0 0; }
+</style>
+</head>
+<body>
+<h1>Your %d in Claude Code</h1>
+`, r.Year, r.Year)
+
+	b.WriteString("<h2>The Year in Numbers</h2>\n<div class=\"grid\">\n")
+	for _, t := range r.Totals {
+		fmt.Fprintf(&b, "  <div class=\"card\"><div class=\"value\">%s</div><div class=\"label\">%s</div></div>\n",
+			esc(t.Value), esc(t.Label))
+	}
+	b.WriteString("</div>\n")
+
+	b.WriteString("<h2>Highlights</h2>\n<div class=\"grid\">\n")
+	for _, h := range r.Highlights {
+		fmt.Fprintf(&b, "  <div class=\"card\"><div class=\"label\">%s</div><div class=\"value\">%s</div><div class=\"detail\">%s</div></div>\n",
+			esc(h.Label), esc(h.Value), esc(h.Detail))
+	}
+	b.WriteString("</div>\n")
+
+	lists := []struct {
+		title string
+		items []wrappedItem
+	}{
+		{"Top Projects", r.Projects},
+		{"Top Tools", r.Tools},
+		{"Most Edited Files", r.Files},
+	}
+	for _, l := range lists {
+		if len(l.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "<h2>%s</h2>\n<table>\n", esc(l.title))
+		for i, it := range l.items {
+			fmt.Fprintf(&b, "  <tr><td>%d</td><td>%s</td><td class=\"n\">%s</td></tr>\n",
+				i+1, esc(it.Name), display.FormatNumber(it.Count))
+		}
+		b.WriteString("</table>\n")
+	}
+
+	b.WriteString("<h2>Your Coding Hours</h2>\n<div class=\"hours\">\n")
+	max := 1
+	for _, n := range r.Hours {
+		if n > max {
+			max = n
+		}
+	}
+	for h, n := range r.Hours {
+		fmt.Fprintf(&b, "  <div style=\"height: %d%%\" title=\"%02d:00 – %s messages\"></div>\n",
+			n*100/max, h, display.FormatNumber(n))
+	}
+	fmt.Fprintf(&b, "</div>\n<p><strong>%s</strong>: %s.</p>\n", esc(r.Personality), esc(r.Blurb))
+
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Budgets is the budgets.json structure. Token limits count output tokens;
+// cost limits are estimated USD.
+type Budgets struct {
+	Daily    *Limit                   `json:"daily,omitempty"`
+	Weekly   *Limit                   `json:"weekly,omitempty"`
+	Monthly  *Limit                   `json:"monthly,omitempty"`
+	Projects map[string]ProjectBudget `json:"projects,omitempty"` // keyed by project name substring
+	WarnAt   float64                  `json:"warnAt,omitempty"`   // percent, default 80
+}
+
+// ProjectBudget holds the limits for projects matching one name
+type ProjectBudget struct {
+	Daily   *Limit `json:"daily,omitempty"`
+	Weekly  *Limit `json:"weekly,omitempty"`
+	Monthly *Limit `json:"monthly,omitempty"`
+}
+
+// Limit caps tokens, cost or both for one period
+type Limit struct {
+	Tokens TokenCount `json:"tokens,omitempty"`
+	Cost   float64    `json:"cost,omitempty"`
+}
+
+// TokenCount accepts plain numbers as well as strings like "750K" or "2M"
+type TokenCount int
+
+func (t *TokenCount) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*t = TokenCount(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("token count must be a number or string like \"2M\"")
+	}
+	n, err := ParseTokenCount(s)
+	if err != nil {
+		return err
+	}
+	*t = TokenCount(n)
+	return nil
+}
+
+// ParseTokenCount parses counts like "500000", "750K" or "1.5M"
+func ParseTokenCount(s string) (int, error) {
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "K"), strings.HasSuffix(s, "k"):
+		mult, s = 1_000, s[:len(s)-1]
+	case strings.HasSuffix(s, "M"), strings.HasSuffix(s, "m"):
+		mult, s = 1_000_000, s[:len(s)-1]
+	case strings.HasSuffix(s, "B"), strings.HasSuffix(s, "b"):
+		mult, s = 1_000_000_000, s[:len(s)-1]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token count %q", s)
+	}
+	return int(f * mult), nil
+}
+
+// LoadBudgets reads budgets.json from the config directory
+func LoadBudgets() (*Budgets, error) {
+	path := BudgetFile()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Budgets
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if b.WarnAt <= 0 {
+		b.WarnAt = 80
+	}
+	return &b, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Goal is a user-defined target for one metric per day, week or month,
+// e.g. at most 200K output tokens per day or at least 4 active weekdays
+type Goal struct {
+	Name   string      `json:"name,omitempty"`
+	Metric string      `json:"metric"` // tokens, messages, sessions, activeDays or activeWeekdays
+	Period string      `json:"period"` // day, week or month
+	Min    *TokenCount `json:"min,omitempty"`
+	Max    *TokenCount `json:"max,omitempty"`
+}
+
+// Title returns the goal's name, or a description built from its target
+func (g Goal) Title() string {
+	if g.Name != "" {
+		return g.Name
+	}
+	metric := g.Metric
+	switch metric {
+	case "activeDays":
+		metric = "active days"
+	case "activeWeekdays":
+		metric = "active weekdays"
+	}
+	switch {
+	case g.Min != nil && g.Max != nil:
+		return fmt.Sprintf("%d–%d %s per %s", *g.Min, *g.Max, metric, g.Period)
+	case g.Min != nil:
+		return fmt.Sprintf("at least %d %s per %s", *g.Min, metric, g.Period)
+	default:
+		return fmt.Sprintf("at most %d %s per %s", *g.Max, metric, g.Period)
+	}
+}
+
+// LoadGoals reads goals.json from the config directory
+func LoadGoals() ([]Goal, error) {
+	path := GoalsFile()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Goals []Goal `json:"goals"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for i, g := range file.Goals {
+		switch g.Metric {
+		case "tokens", "messages", "sessions", "activeDays", "activeWeekdays":
+		default:
+			return nil, fmt.Errorf("%s: goal %d: invalid metric %q", path, i+1, g.Metric)
+		}
+		switch g.Period {
+		case "day", "week", "month":
+		default:
+			return nil, fmt.Errorf("%s: goal %d: invalid period %q (want day, week or month)", path, i+1, g.Period)
+		}
+		if g.Min == nil && g.Max == nil {
+			return nil, fmt.Errorf("%s: goal %d: needs min or max", path, i+1)
+		}
+	}
+	return file.Goals, nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Dir returns the path to ccs's own config directory,
+// $XDG_CONFIG_HOME/ccs or ~/.config/ccs
+func Dir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "ccs")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "ccs")
+}
+
+// SettingsFile returns the path to config.json
+func SettingsFile() string {
+	return filepath.Join(Dir(), "config.json")
+}
+
+// BudgetFile returns the path to budgets.json
+func BudgetFile() string {
+	return filepath.Join(Dir(), "budgets.json")
+}
+
+// GoalsFile returns the path to goals.json
+func GoalsFile() string {
+	return filepath.Join(Dir(), "goals.json")
+}
+
+// DataDir returns the path to ccs's data directory,
+// $XDG_DATA_HOME/ccs or ~/.local/share/ccs
+func DataDir() string {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "ccs")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "share", "ccs")
+}
+
+// ArchiveDir returns the path to the session archive, which mirrors
+// ~/.claude/projects/ under DataDir
+func ArchiveDir() string {
+	return filepath.Join(DataDir(), "archive")
+}
+
+// CacheDir returns the path to ccs's cache directory,
+// $XDG_CACHE_HOME/ccs or ~/.cache/ccs
+func CacheDir() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "ccs")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cache", "ccs")
+}
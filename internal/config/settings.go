@@ -0,0 +1,344 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Settings is ccs's effective configuration: built-in defaults, overridden
+// by config.json, then by CCS_* environment variables, then by global flags
+type Settings struct {
+	Output         string // terminal, json or md
+	Timezone       string // IANA zone name for the timestamp-based views, "" for the system zone (local)
+	WeekStart      string // monday or sunday
+	Color          string // auto, always or never
+	SessionsLimit  int    // sessions listed by `ccs sessions` and `ccs all`
+	TokensDays     int    // days in the daily token charts
+	PeriodSessions int    // sessions listed by today/week/month
+	PeakHours      int    // hours listed under Peak Hours
+	Truncate       int    // characters of a prompt shown in session details
+	Projects       ProjectSettings
+
+	sources map[string]string // key -> where its value came from
+}
+
+// ProjectSettings renames, hides and groups projects. Every pattern is
+// matched against a project's path and its directory name under
+// ~/.claude/projects; * matches any characters including / and ? matches
+// one character.
+type ProjectSettings struct {
+	Aliases map[string]string   `json:"aliases,omitempty"` // pattern -> display name
+	Exclude []string            `json:"exclude,omitempty"` // patterns of projects to hide
+	Groups  map[string][]string `json:"groups,omitempty"`  // group name -> patterns
+}
+
+// Setting describes one configurable value and how to set it at each layer
+type Setting struct {
+	Key  string // config.json key
+	Env  string // environment variable
+	Flag string // global flag, used as --flag=value
+	Help string // flag usage; a `NAME` in backquotes names the value
+	get  func(*Settings) string
+	set  func(*Settings, string) error
+}
+
+// SettingsTable lists every setting in the order `ccs config show` prints
+var SettingsTable = []Setting{
+	{"output", "CCS_OUTPUT", "output", "Output `FORMAT`: terminal, json or md",
+		func(s *Settings) string { return s.Output },
+		func(s *Settings, v string) error {
+			return oneOf(&s.Output, v, "terminal", "json", "md")
+		}},
+	{"timezone", "CCS_TIMEZONE", "tz", "Time zone `ZONE` for hours, blocks, timesheet and wrapped, e.g. Europe/Berlin",
+		func(s *Settings) string {
+			if s.Timezone == "" {
+				return "local"
+			}
+			return s.Timezone
+		},
+		func(s *Settings, v string) error {
+			if strings.EqualFold(v, "local") {
+				s.Timezone = ""
+				return nil
+			}
+			if _, err := time.LoadLocation(v); err != nil {
+				return err
+			}
+			s.Timezone = v
+			return nil
+		}},
+	{"weekStart", "CCS_WEEK_START", "week-start", "First `DAY` of the week: monday or sunday",
+		func(s *Settings) string { return s.WeekStart },
+		func(s *Settings, v string) error {
+			switch strings.ToLower(v) {
+			case "mon":
+				v = "monday"
+			case "sun":
+				v = "sunday"
+			}
+			return oneOf(&s.WeekStart, v, "monday", "sunday")
+		}},
+	{"color", "CCS_COLOR", "color", "Color `MODE`: auto, always or never",
+		func(s *Settings) string { return s.Color },
+		func(s *Settings, v string) error {
+			return oneOf(&s.Color, v, "auto", "always", "never")
+		}},
+	{"sessionsLimit", "CCS_SESSIONS_LIMIT", "sessions-limit", "List `N` sessions in sessions and all",
+		func(s *Settings) string { return strconv.Itoa(s.SessionsLimit) },
+		func(s *Settings, v string) error { return positive(&s.SessionsLimit, v) }},
+	{"tokensDays", "CCS_TOKENS_DAYS", "tokens-days", "Chart `N` days of daily tokens",
+		func(s *Settings) string { return strconv.Itoa(s.TokensDays) },
+		func(s *Settings, v string) error { return positive(&s.TokensDays, v) }},
+	{"periodSessions", "CCS_PERIOD_SESSIONS", "period-sessions", "List `N` sessions in today, week and month",
+		func(s *Settings) string { return strconv.Itoa(s.PeriodSessions) },
+		func(s *Settings, v string) error { return positive(&s.PeriodSessions, v) }},
+	{"peakHours", "CCS_PEAK_HOURS", "peak-hours", "List `N` peak hours",
+		func(s *Settings) string { return strconv.Itoa(s.PeakHours) },
+		func(s *Settings, v string) error { return positive(&s.PeakHours, v) }},
+	{"truncate", "CCS_TRUNCATE", "truncate", "Show `N` characters of each prompt in session",
+		func(s *Settings) string { return strconv.Itoa(s.Truncate) },
+		func(s *Settings, v string) error { return positive(&s.Truncate, v) }},
+}
+
+// Value returns the setting's current value as text
+func (st Setting) Value(s *Settings) string { return st.get(s) }
+
+// DefaultSettings returns the built-in defaults
+func DefaultSettings() *Settings {
+	s := &Settings{
+		Output:         "terminal",
+		WeekStart:      "monday",
+		Color:          "auto",
+		SessionsLimit:  20,
+		TokensDays:     14,
+		PeriodSessions: 15,
+		PeakHours:      5,
+		Truncate:       70,
+		sources:        make(map[string]string),
+	}
+	for _, st := range SettingsTable {
+		s.sources[st.Key] = "default"
+	}
+	return s
+}
+
+// LoadSettings returns the defaults overridden by config.json and then by
+// CCS_* environment variables. A missing config.json is not an error.
+func LoadSettings() (*Settings, error) {
+	s := DefaultSettings()
+	if err := s.loadFile(SettingsFile()); err != nil {
+		return nil, err
+	}
+	for _, st := range SettingsTable {
+		if v, ok := os.LookupEnv(st.Env); ok && v != "" {
+			if err := st.set(s, v); err != nil {
+				return nil, fmt.Errorf("%s: %w", st.Env, err)
+			}
+			s.sources[st.Key] = "env " + st.Env
+		}
+	}
+	return s, nil
+}
+
+func (s *Settings) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for key, value := range raw {
+		if key == "projects" {
+			if err := json.Unmarshal(value, &s.Projects); err != nil {
+				return fmt.Errorf("parsing %s: projects: %w", path, err)
+			}
+			continue
+		}
+		st, ok := lookupSetting(func(st Setting) bool { return st.Key == key })
+		if !ok {
+			return fmt.Errorf("%s: unknown setting %q", path, key)
+		}
+		v := strings.TrimSpace(string(value))
+		var str string
+		if json.Unmarshal(value, &str) == nil {
+			v = str
+		}
+		if err := st.set(s, v); err != nil {
+			return fmt.Errorf("%s: %s: %w", path, key, err)
+		}
+		s.sources[key] = "config.json"
+	}
+
+	for pattern, name := range s.Projects.Aliases {
+		if pattern == "" || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%s: alias %q: needs a pattern and a name", path, pattern)
+		}
+	}
+	for name, patterns := range s.Projects.Groups {
+		if strings.TrimSpace(name) == "" || len(patterns) == 0 {
+			return fmt.Errorf("%s: group %q: needs a name and at least one pattern", path, name)
+		}
+	}
+	return nil
+}
+
+// SetFlag applies the global flag --name=value
+func (s *Settings) SetFlag(name, value string) error {
+	st, ok := lookupSetting(func(st Setting) bool { return st.Flag == name })
+	if !ok {
+		return fmt.Errorf("unknown setting flag --%s", name)
+	}
+	if err := st.set(s, value); err != nil {
+		return err
+	}
+	s.sources[st.Key] = "flag --" + name
+	return nil
+}
+
+// SetOutput sets the output format from the --json or --md shorthand
+func (s *Settings) SetOutput(format, flag string) {
+	s.Output = format
+	s.sources["output"] = "flag " + flag
+}
+
+// Source describes where a setting's value came from: default,
+// config.json, env NAME or flag --name
+func (s *Settings) Source(key string) string {
+	return s.sources[key]
+}
+
+// Location returns the configured time zone, or the system's
+func (s *Settings) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.Local
+	}
+	if loc, err := time.LoadLocation(s.Timezone); err == nil {
+		return loc
+	}
+	return time.Local
+}
+
+// FirstWeekday returns the configured start of the week
+func (s *Settings) FirstWeekday() time.Weekday {
+	if s.WeekStart == "sunday" {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+func lookupSetting(match func(Setting) bool) (Setting, bool) {
+	for _, st := range SettingsTable {
+		if match(st) {
+			return st, true
+		}
+	}
+	return Setting{}, false
+}
+
+func oneOf(dst *string, v string, allowed ...string) error {
+	v = strings.ToLower(v)
+	for _, a := range allowed {
+		if v == a {
+			*dst = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%q (want %s)", v, strings.Join(allowed, ", "))
+}
+
+func positive(dst *int, v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("%q (want a positive number)", v)
+	}
+	*dst = n
+	return nil
+}
+
+// Excluded reports whether a project is hidden by an exclude pattern
+func (p ProjectSettings) Excluded(path, dirName string) bool {
+	for _, pattern := range p.Exclude {
+		if matchProject(pattern, path, dirName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Alias returns a project's configured display name, or "". Exact
+// patterns win over globs; globs are tried in sorted order.
+func (p ProjectSettings) Alias(path, dirName string) string {
+	if name, ok := p.Aliases[path]; ok && path != "" {
+		return name
+	}
+	if name, ok := p.Aliases[dirName]; ok && dirName != "" {
+		return name
+	}
+	for _, pattern := range sortedKeys(p.Aliases) {
+		if matchProject(pattern, path, dirName) {
+			return p.Aliases[pattern]
+		}
+	}
+	return ""
+}
+
+// Group returns the name of the first group, in sorted order, that
+// contains the project, or ""
+func (p ProjectSettings) Group(path, dirName string) string {
+	for _, name := range sortedKeys(p.Groups) {
+		for _, pattern := range p.Groups[name] {
+			if matchProject(pattern, path, dirName) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+func matchProject(pattern, path, dirName string) bool {
+	return (path != "" && MatchGlob(pattern, path)) || (dirName != "" && MatchGlob(pattern, dirName))
+}
+
+// MatchGlob reports whether s matches pattern as a whole, where * matches
+// any run of characters including / and ? matches a single character
+func MatchGlob(pattern, s string) bool {
+	if !strings.ContainsAny(pattern, "*?") {
+		return pattern == s
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	ok, _ := regexp.MatchString(b.String(), s)
+	return ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
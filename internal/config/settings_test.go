@@ -0,0 +1,110 @@
+package config
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"/Users/me/shop", "/Users/me/shop", true},
+		{"/Users/me/shop", "/Users/me/shop2", false},
+		{"*scratch*", "/tmp/scratch-dir", true},
+		{"*scratch*", "-Users-me-tmp-scratch-dir", true},
+		{"*/shop", "/Users/me/work/shop", true},
+		{"*/shop", "/Users/me/work/shop/api", false},
+		{"/tmp/*", "/tmp/a/b/c", true}, // * crosses /
+		{"/tmp/*", "/tmp", false},
+		{"app?", "app1", true},
+		{"app?", "app12", false},
+		{"a.b", "axb", false}, // regexp metacharacters are literal
+		{"a.b*", "a.bc", true},
+		{"[x]*", "[x]y", true},
+		{"", "", true},
+		{"*", "", true},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestProjectSettingsAlias(t *testing.T) {
+	p := ProjectSettings{
+		Aliases: map[string]string{
+			"/Users/me/work/shop":               "Shop (exact)",
+			"*/shop":                            "Shop (glob)",
+			"*/work/*":                          "Work",
+			"*/w*":                              "W",
+			"-Users-me-work-clientA-legacy-app": "Client A",
+		},
+	}
+	tests := []struct {
+		name, path, dirName string
+		want                string
+	}{
+		{"exact path wins over globs", "/Users/me/work/shop", "-Users-me-work-shop", "Shop (exact)"},
+		{"exact dir name wins over globs", "/Users/me/work/clientA/legacy-app", "-Users-me-work-clientA-legacy-app", "Client A"},
+		{"globs are tried in sorted order", "/Users/me/other/shop", "", "Shop (glob)"},
+		{"first sorted glob wins among several", "/Users/me/work/api", "", "W"}, // "*/w*" < "*/work/*"
+		{"no match", "/srv/api", "-srv-api", ""},
+		{"empty path doesn't match an empty key", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Alias(tt.path, tt.dirName); got != tt.want {
+				t.Errorf("Alias(%q, %q) = %q, want %q", tt.path, tt.dirName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectSettingsGroup(t *testing.T) {
+	p := ProjectSettings{
+		Groups: map[string][]string{
+			"Customer X": {"/Users/me/work/clientA/*", "/Users/me/work/shop"},
+			"All work":   {"/Users/me/work/*"},
+			"Scratch":    {"*scratch*"},
+		},
+	}
+	tests := []struct {
+		path, dirName string
+		want          string
+	}{
+		// "All work" sorts before "Customer X", so it wins for both
+		{"/Users/me/work/shop", "", "All work"},
+		{"/Users/me/work/clientA/app", "", "All work"},
+		{"/tmp/x", "-tmp-scratch", "Scratch"},
+		{"/srv/api", "-srv-api", ""},
+	}
+	for _, tt := range tests {
+		if got := p.Group(tt.path, tt.dirName); got != tt.want {
+			t.Errorf("Group(%q, %q) = %q, want %q", tt.path, tt.dirName, got, tt.want)
+		}
+	}
+
+	// Without an overlapping group, members of a group match by path or dir
+	p = ProjectSettings{Groups: map[string][]string{"Customer X": {"/Users/me/work/shop", "-Users-me-work-clientA-*"}}}
+	if got := p.Group("", "-Users-me-work-clientA-legacy-app"); got != "Customer X" {
+		t.Errorf("Group by dir name = %q, want %q", got, "Customer X")
+	}
+}
+
+func TestProjectSettingsExcluded(t *testing.T) {
+	p := ProjectSettings{Exclude: []string{"*scratch*", "/tmp/*"}}
+	tests := []struct {
+		path, dirName string
+		want          bool
+	}{
+		{"/Users/me/scratch", "", true},
+		{"", "-Users-me-tmp-scratch-dir", true},
+		{"/tmp/demo", "-tmp-demo", true},
+		{"/Users/me/work/shop", "-Users-me-work-shop", false},
+	}
+	for _, tt := range tests {
+		if got := p.Excluded(tt.path, tt.dirName); got != tt.want {
+			t.Errorf("Excluded(%q, %q) = %v, want %v", tt.path, tt.dirName, got, tt.want)
+		}
+	}
+}
@@ -24,6 +24,25 @@ func Bar(value, max, width int) string {
 	return Green(repeat("█", filled)) + Dim(repeat("░", width-filled))
 }
 
+// Sparkline renders values as a row of block characters scaled to the largest
+func Sparkline(values []int) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	out := make([]rune, len(values))
+	for i, v := range values {
+		out[i] = levels[0]
+		if max > 0 && v > 0 {
+			out[i] = levels[(v*(len(levels)-1)+max-1)/max]
+		}
+	}
+	return string(out)
+}
+
 func repeat(s string, n int) string {
 	result := ""
 	for i := 0; i < n; i++ {
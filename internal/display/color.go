@@ -1,6 +1,7 @@
 package display
 
 import (
+	"fmt"
 	"os"
 )
 
@@ -43,6 +44,22 @@ func shouldUseColor() bool {
 	return fi.Mode()&os.ModeCharDevice != 0
 }
 
+// SetColorMode overrides terminal detection: "always" forces colors on,
+// "never" turns them off and "auto" keeps the NO_COLOR and TTY checks
+func SetColorMode(mode string) {
+	switch mode {
+	case "always":
+		colorEnabled = true
+	case "never":
+		colorEnabled = false
+	default:
+		colorEnabled = shouldUseColor()
+	}
+}
+
+// ColorEnabled reports whether ANSI colors are written
+func ColorEnabled() bool { return colorEnabled }
+
 func apply(code, s string) string {
 	if !colorEnabled {
 		return s
@@ -66,3 +83,8 @@ func BoldYellow(s string) string  { return apply(bold+yellow, s) }
 func BoldBlue(s string) string    { return apply(bold+blue, s) }
 func BoldMagenta(s string) string { return apply(bold+magenta, s) }
 func BoldWhite(s string) string   { return apply(bold+white, s) }
+
+// Color256 colors s with a foreground from the 256-color palette
+func Color256(code int, s string) string {
+	return apply(fmt.Sprintf("\033[38;5;%dm", code), s)
+}
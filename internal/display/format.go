@@ -120,3 +120,8 @@ func FormatTokens(n int) string {
 		return fmt.Sprintf("%d", n)
 	}
 }
+
+// FormatCost formats a USD amount: 12.3456 → "$12.35"
+func FormatCost(usd float64) string {
+	return fmt.Sprintf("$%.2f", usd)
+}
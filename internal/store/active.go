@@ -0,0 +1,64 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// IdleGap is the longest pause between consecutive messages that still
+// counts as active time. Longer gaps are cut from active durations.
+var IdleGap = 30 * time.Minute
+
+// ActiveDuration sums the gaps between consecutive timestamps that are at
+// most idle long. A session left open over the weekend therefore only
+// counts the time actually spent messaging.
+func ActiveDuration(timestamps []time.Time, idle time.Duration) time.Duration {
+	if len(timestamps) < 2 {
+		return 0
+	}
+	ts := timestamps
+	if !sort.SliceIsSorted(ts, func(i, j int) bool { return ts[i].Before(ts[j]) }) {
+		ts = append([]time.Time(nil), timestamps...)
+		sort.Slice(ts, func(i, j int) bool { return ts[i].Before(ts[j]) })
+	}
+
+	var active time.Duration
+	for i := 1; i < len(ts); i++ {
+		if gap := ts[i].Sub(ts[i-1]); gap <= idle {
+			active += gap
+		}
+	}
+	return active
+}
+
+// WallDuration returns the time between the first and last message
+func (ss *SessionStats) WallDuration() time.Duration {
+	if ss.StartedAt.IsZero() || ss.EndedAt.IsZero() {
+		return 0
+	}
+	return ss.EndedAt.Sub(ss.StartedAt)
+}
+
+// ActiveDuration returns the session's active time using IdleGap
+func (ss *SessionStats) ActiveDuration() time.Duration {
+	return ActiveDuration(ss.Timestamps, IdleGap)
+}
+
+// WallDuration returns the time between the first and last message
+func (d *SessionDetail) WallDuration() time.Duration {
+	if d.StartedAt.IsZero() || d.EndedAt.IsZero() {
+		return 0
+	}
+	return d.EndedAt.Sub(d.StartedAt)
+}
+
+// ActiveDuration returns the session's active time using IdleGap
+func (d *SessionDetail) ActiveDuration() time.Duration {
+	var ts []time.Time
+	for _, m := range d.Messages {
+		if !m.Timestamp.IsZero() {
+			ts = append(ts, m.Timestamp)
+		}
+	}
+	return ActiveDuration(ts, IdleGap)
+}
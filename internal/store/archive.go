@@ -0,0 +1,322 @@
+package store
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/claude"
+	"github.com/dkd/ccs/internal/config"
+)
+
+// ArchiveManifest records what has been copied into the archive, so that
+// unchanged transcripts are skipped on the next run
+type ArchiveManifest struct {
+	Version int                     `json:"version"`
+	Files   map[string]ArchivedFile `json:"files"` // keyed by <project dir>/<file name>
+}
+
+// ArchivedFile is the archived copy of one live session file
+type ArchivedFile struct {
+	Stored     string    `json:"stored"` // relative to the archive's projects dir
+	Size       int64     `json:"size"`   // size of the live file when copied
+	ModTime    time.Time `json:"modTime"`
+	ArchivedAt time.Time `json:"archivedAt"`
+}
+
+// ArchiveResult summarizes one archive run
+type ArchiveResult struct {
+	Added     int
+	Updated   int
+	Unchanged int
+	Indexes   int
+	Bytes     int64 // bytes written to the archive
+}
+
+// ArchiveStatus describes the archive's contents
+type ArchiveStatus struct {
+	Dir          string
+	Sessions     int
+	ArchiveOnly  int // sessions whose live transcript is gone
+	Bytes        int64
+	LastArchived time.Time
+}
+
+func archiveManifestPath() string {
+	return filepath.Join(config.ArchiveDir(), "manifest.json")
+}
+
+// LoadArchiveManifest reads the archive manifest. A missing manifest is
+// returned as an empty one.
+func LoadArchiveManifest() (*ArchiveManifest, error) {
+	m := &ArchiveManifest{Version: 1, Files: make(map[string]ArchivedFile)}
+	data, err := os.ReadFile(archiveManifestPath())
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", archiveManifestPath(), err)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]ArchivedFile)
+	}
+	return m, nil
+}
+
+func saveArchiveManifest(m *ArchiveManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(archiveManifestPath(), data)
+}
+
+// ArchiveSessions copies new and changed session transcripts and index files
+// from ~/.claude/projects into the archive, gzipping new copies if compress
+// is set. Files Claude Code has deleted stay in the archive.
+func ArchiveSessions(compress bool, progress func(done, total int)) (*ArchiveResult, error) {
+	manifest, err := LoadArchiveManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	projectsDir := claude.ProjectsDir()
+	entries, err := os.ReadDir(projectsDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading projects dir: %w", err)
+	}
+
+	type liveFile struct {
+		dirName, name, path string
+	}
+	var files []liveFile
+	var dirNames []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dirNames = append(dirNames, entry.Name())
+		for _, f := range globSessionFiles(filepath.Join(projectsDir, entry.Name())) {
+			files = append(files, liveFile{entry.Name(), filepath.Base(f), f})
+		}
+	}
+
+	res := &ArchiveResult{}
+	root := archiveProjectsDir()
+	for i, f := range files {
+		if progress != nil {
+			progress(i+1, len(files))
+		}
+
+		info, err := os.Stat(f.path)
+		if err != nil {
+			continue
+		}
+		key := f.dirName + "/" + f.name
+		prev, known := manifest.Files[key]
+		if known && prev.Size == info.Size() && prev.ModTime.Equal(info.ModTime()) {
+			if _, err := os.Stat(filepath.Join(root, prev.Stored)); err == nil {
+				res.Unchanged++
+				continue
+			}
+		}
+
+		// Transcripts that are already compressed are copied as they are
+		gz := compress && strings.HasSuffix(f.name, ".jsonl")
+		stored := key
+		if gz {
+			stored += ".gz"
+		}
+		n, err := archiveFile(f.path, filepath.Join(root, stored), gz, info.ModTime())
+		if err != nil {
+			return res, fmt.Errorf("archiving %s: %w", f.path, err)
+		}
+		if known && prev.Stored != stored {
+			os.Remove(filepath.Join(root, prev.Stored))
+		}
+		forgetOtherCopies(manifest, key, root)
+		manifest.Files[key] = ArchivedFile{
+			Stored:     stored,
+			Size:       info.Size(),
+			ModTime:    info.ModTime(),
+			ArchivedAt: time.Now(),
+		}
+		res.Bytes += n
+		if known {
+			res.Updated++
+		} else {
+			res.Added++
+		}
+	}
+
+	for _, dirName := range dirNames {
+		ok, err := archiveIndex(dirName)
+		if err != nil {
+			return res, err
+		}
+		if ok {
+			res.Indexes++
+		}
+	}
+
+	return res, saveArchiveManifest(manifest)
+}
+
+// archiveFile copies src to dst, optionally gzipped, and gives the copy
+// src's modification time so mtime ordering survives archiving
+func archiveFile(src, dst string, compress bool, mtime time.Time) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return 0, err
+	}
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, err
+	}
+
+	var w io.Writer = out
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(out)
+		w = zw
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return 0, err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			out.Close()
+			os.Remove(tmp)
+			return 0, err
+		}
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		return 0, err
+	}
+	os.Chtimes(dst, mtime, mtime)
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// archiveIndex merges a project's live sessions-index.json into its archived
+// copy: live entries replace archived ones, entries for sessions Claude Code
+// has dropped are kept. It reports whether the project has a live index.
+func archiveIndex(dirName string) (bool, error) {
+	live, err := loadSessionIndex(filepath.Join(claude.ProjectsDir(), dirName, "sessions-index.json"))
+	if err != nil {
+		return false, nil
+	}
+
+	dst := filepath.Join(archiveProjectsDir(), dirName, "sessions-index.json")
+	merged := *live
+	if archived, err := loadSessionIndex(dst); err == nil {
+		seen := make(map[string]bool)
+		for _, e := range live.Entries {
+			seen[e.SessionID] = true
+		}
+		for _, e := range archived.Entries {
+			if !seen[e.SessionID] {
+				merged.Entries = append(merged.Entries, e)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(&merged, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return false, err
+	}
+	return true, writeFileAtomic(dst, data)
+}
+
+// GetArchiveStatus counts archived sessions and those only left in the archive
+func GetArchiveStatus() (*ArchiveStatus, error) {
+	manifest, err := LoadArchiveManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	st := &ArchiveStatus{Dir: config.ArchiveDir()}
+	root := archiveProjectsDir()
+	for key, f := range manifest.Files {
+		st.Sessions++
+		if info, err := os.Stat(filepath.Join(root, f.Stored)); err == nil {
+			st.Bytes += info.Size()
+		}
+		if !liveSessionExists(key) {
+			st.ArchiveOnly++
+		}
+		if f.ArchivedAt.After(st.LastArchived) {
+			st.LastArchived = f.ArchivedAt
+		}
+	}
+	return st, nil
+}
+
+// forgetOtherCopies drops manifest entries, and their archived files, that
+// hold the same session as key under another name, e.g. the plain copy of a
+// transcript that has since been compressed in place
+func forgetOtherCopies(m *ArchiveManifest, key, root string) {
+	dir, id := filepath.Dir(key), sessionIDFromPath(key)
+	for other, f := range m.Files {
+		if other == key || filepath.Dir(other) != dir || sessionIDFromPath(other) != id {
+			continue
+		}
+		if f.Stored != m.Files[key].Stored {
+			os.Remove(filepath.Join(root, f.Stored))
+		}
+		delete(m.Files, other)
+	}
+}
+
+// liveSessionExists reports whether the session of a manifest key still has
+// a live transcript in any of the supported forms
+func liveSessionExists(key string) bool {
+	dir := filepath.Join(claude.ProjectsDir(), filepath.Dir(key))
+	id := sessionIDFromPath(key)
+	for _, ext := range sessionFileExts {
+		if _, err := os.Stat(filepath.Join(dir, id+ext)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// BlockDuration is the length of a subscription rate-limit window
+const BlockDuration = 5 * time.Hour
+
+// UsageBlock is one 5-hour billing window. It starts at the first message
+// sent after the previous window expired.
+type UsageBlock struct {
+	Start   time.Time
+	End     time.Time // Start + BlockDuration
+	LastAt  time.Time // last message in the block
+	Entries int
+	Models  map[string]ModelUsage
+}
+
+// Total sums usage across all models
+func (b *UsageBlock) Total() ModelUsage {
+	return ProjectUsage{Models: b.Models}.Total()
+}
+
+// Cost returns the estimated API cost of the block in USD
+func (b *UsageBlock) Cost() float64 {
+	return EstimateCostByModel(b.Models)
+}
+
+// IsActive reports whether the window is still open at now
+func (b *UsageBlock) IsActive(now time.Time) bool {
+	return !now.Before(b.Start) && now.Before(b.End)
+}
+
+// LoadUsageEvents returns the de-duplicated usage of every API response at
+// or after since, across all projects, sorted by time
+func LoadUsageEvents(since time.Time) ([]UsageEvent, error) {
+	projects, err := listProjectFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, pf := range projects {
+		files = append(files, pf.Files...)
+	}
+	sortByMtime(files)
+
+	dedup := NewDeduper()
+	var events []UsageEvent
+	for _, path := range files {
+		// Older files are scanned too so copied lines are recognized
+		ss, err := ScanSessionStats(path, dedup)
+		if err != nil {
+			continue
+		}
+		for _, ev := range ss.Usage {
+			if !ev.Timestamp.Before(since) {
+				events = append(events, ev)
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events, nil
+}
+
+// GroupBlocks splits time-sorted usage events into 5-hour blocks. A new block
+// starts with the first event after the previous block's window ended.
+func GroupBlocks(events []UsageEvent) []UsageBlock {
+	var blocks []UsageBlock
+	var cur *UsageBlock
+	for _, ev := range events {
+		if cur == nil || !ev.Timestamp.Before(cur.End) {
+			blocks = append(blocks, UsageBlock{
+				Start:  ev.Timestamp,
+				End:    ev.Timestamp.Add(BlockDuration),
+				Models: make(map[string]ModelUsage),
+			})
+			cur = &blocks[len(blocks)-1]
+		}
+		mu := cur.Models[ev.Model]
+		mu.InputTokens += ev.Usage.InputTokens
+		mu.OutputTokens += ev.Usage.OutputTokens
+		mu.CacheReadInputTokens += ev.Usage.CacheReadInputTokens
+		mu.CacheCreationInputTokens += ev.Usage.CacheCreationInputTokens
+		cur.Models[ev.Model] = mu
+		cur.Entries++
+		cur.LastAt = ev.Timestamp
+	}
+	return blocks
+}
+
+// BlockProjection extrapolates the active block's burn rate to its end
+type BlockProjection struct {
+	Elapsed         time.Duration
+	Remaining       time.Duration
+	TokensPerMinute float64
+	CostPerHour     float64
+	ProjectedTokens int
+	ProjectedCost   float64
+}
+
+// ProjectBlock estimates where the active block will end up at the current
+// burn rate
+func ProjectBlock(b *UsageBlock, now time.Time) BlockProjection {
+	p := BlockProjection{
+		Elapsed:   now.Sub(b.Start),
+		Remaining: b.End.Sub(now),
+	}
+	tokens := b.Total().TotalTokens()
+	cost := b.Cost()
+	p.ProjectedTokens = tokens
+	p.ProjectedCost = cost
+
+	// Need at least a minute of history for a meaningful rate
+	if p.Elapsed < time.Minute {
+		return p
+	}
+	minutes := p.Elapsed.Minutes()
+	p.TokensPerMinute = float64(tokens) / minutes
+	p.CostPerHour = cost / minutes * 60
+	p.ProjectedTokens = tokens + int(p.TokensPerMinute*p.Remaining.Minutes())
+	p.ProjectedCost = cost + cost/minutes*p.Remaining.Minutes()
+	return p
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupBlocks(t *testing.T) {
+	base := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return base.Add(d) }
+
+	tests := []struct {
+		name       string
+		offsets    []time.Duration
+		wantStarts []time.Duration
+		wantCounts []int
+	}{
+		{"no events", nil, nil, nil},
+		{"single event", []time.Duration{0}, []time.Duration{0}, []int{1}},
+		{
+			"just inside the window",
+			[]time.Duration{0, BlockDuration - time.Second},
+			[]time.Duration{0},
+			[]int{2},
+		},
+		{
+			"exactly at the window end starts a new block",
+			[]time.Duration{0, BlockDuration},
+			[]time.Duration{0, BlockDuration},
+			[]int{1, 1},
+		},
+		{
+			"next block starts at its first event, not on the 5h grid",
+			[]time.Duration{0, time.Hour, 7 * time.Hour, 11*time.Hour + 59*time.Minute, 12 * time.Hour},
+			[]time.Duration{0, 7 * time.Hour, 12 * time.Hour},
+			[]int{2, 2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []UsageEvent
+			for _, off := range tt.offsets {
+				events = append(events, UsageEvent{
+					Timestamp: at(off),
+					Model:     "claude-x",
+					Usage:     ModelUsage{InputTokens: 1, OutputTokens: 10},
+				})
+			}
+
+			blocks := GroupBlocks(events)
+			if len(blocks) != len(tt.wantStarts) {
+				t.Fatalf("got %d blocks, want %d", len(blocks), len(tt.wantStarts))
+			}
+			for i, b := range blocks {
+				if want := at(tt.wantStarts[i]); !b.Start.Equal(want) {
+					t.Errorf("block %d starts %s, want %s", i, b.Start, want)
+				}
+				if want := b.Start.Add(BlockDuration); !b.End.Equal(want) {
+					t.Errorf("block %d ends %s, want %s", i, b.End, want)
+				}
+				if b.Entries != tt.wantCounts[i] {
+					t.Errorf("block %d has %d entries, want %d", i, b.Entries, tt.wantCounts[i])
+				}
+				if got := b.Models["claude-x"].OutputTokens; got != 10*tt.wantCounts[i] {
+					t.Errorf("block %d has %d output tokens, want %d", i, got, 10*tt.wantCounts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUsageBlockIsActive(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	b := UsageBlock{Start: start, End: start.Add(BlockDuration)}
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{start.Add(-time.Second), false},
+		{start, true},
+		{b.End.Add(-time.Second), true},
+		{b.End, false},
+	}
+	for _, tt := range tests {
+		if got := b.IsActive(tt.now); got != tt.want {
+			t.Errorf("IsActive(%s) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
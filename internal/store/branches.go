@@ -0,0 +1,126 @@
+package store
+
+import (
+	"regexp"
+	"sort"
+	"time"
+)
+
+// DefaultTicketPattern matches Jira-style ticket keys such as PROJ-123
+const DefaultTicketPattern = `[A-Z][A-Z0-9]+-[0-9]+`
+
+// BranchStats aggregates sessions on one git branch of one project
+type BranchStats struct {
+	Project    string
+	Branch     string
+	Ticket     string
+	Sessions   int
+	Messages   int
+	TokensIn   int
+	TokensOut  int
+	Duration   time.Duration // wall-clock
+	ActiveTime time.Duration
+	LastActive time.Time
+}
+
+// ComputeBranchStats scans all session files and aggregates them per
+// project and git branch, crediting each message to the branch recorded on
+// it, so a session that switches branches counts towards each of them.
+// Messages without a branch are grouped under "".
+// If ticket is non-nil, the first match in each branch name is recorded
+// as its ticket key.
+func ComputeBranchStats(projectFilter string, ticket *regexp.Regexp) ([]BranchStats, error) {
+	projects, err := listProjectFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*BranchStats)
+	dedup := NewDeduper()
+
+	for _, pf := range projects {
+		if pf.Excluded || !matchesProject(projectFilter, pf.Path, pf.DirName) {
+			continue
+		}
+
+		sortByMtime(pf.Files)
+		for _, path := range pf.Files {
+			ss, err := ScanSessionStats(path, dedup)
+			if err != nil {
+				continue
+			}
+			for name, b := range ss.Branches {
+				if b.Messages == 0 {
+					continue
+				}
+				key := pf.Path + "\x00" + name
+				bs, ok := byKey[key]
+				if !ok {
+					bs = &BranchStats{Project: ProjectName(pf.Path, pf.DirName), Branch: name}
+					if ticket != nil {
+						bs.Ticket = ticket.FindString(name)
+					}
+					byKey[key] = bs
+				}
+
+				bs.Sessions++
+				bs.Messages += b.Messages
+				bs.TokensIn += b.TokensIn
+				bs.TokensOut += b.TokensOut
+				bs.Duration += b.WallDuration()
+				bs.ActiveTime += b.ActiveDuration()
+				if ss.EndedAt.After(bs.LastActive) {
+					bs.LastActive = ss.EndedAt
+				}
+			}
+		}
+	}
+
+	var out []BranchStats
+	for _, bs := range byKey {
+		out = append(out, *bs)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Project != out[j].Project {
+			return out[i].Project < out[j].Project
+		}
+		return out[i].Messages > out[j].Messages
+	})
+	return out, nil
+}
+
+// GroupByTicket sums branch stats per ticket key, dropping branches without one.
+// The result is sorted by message count descending.
+func GroupByTicket(branches []BranchStats) []BranchStats {
+	byTicket := make(map[string]*BranchStats)
+	for _, b := range branches {
+		if b.Ticket == "" {
+			continue
+		}
+		t, ok := byTicket[b.Ticket]
+		if !ok {
+			t = &BranchStats{Ticket: b.Ticket, Project: b.Project, Branch: b.Branch}
+			byTicket[b.Ticket] = t
+		} else if t.Project != b.Project {
+			t.Project = "(multiple)"
+		}
+		t.Sessions += b.Sessions
+		t.Messages += b.Messages
+		t.TokensIn += b.TokensIn
+		t.TokensOut += b.TokensOut
+		t.Duration += b.Duration
+		t.ActiveTime += b.ActiveTime
+		if b.LastActive.After(t.LastActive) {
+			t.LastActive = b.LastActive
+		}
+	}
+
+	var out []BranchStats
+	for _, t := range byTicket {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Messages > out[j].Messages
+	})
+	return out
+}
@@ -0,0 +1,59 @@
+package store
+
+// PeriodTotals aggregates the stats cache over a range of dates
+type PeriodTotals struct {
+	Sessions      int
+	Messages      int
+	ToolCalls     int
+	TokensByModel map[string]int // output tokens
+	Projects      map[string]int // project path -> total tokens, nil without per-project data
+}
+
+// Totals sums daily activity, output tokens and per-project tokens for dates
+// from through to (YYYY-MM-DD, inclusive)
+func (s *StatsCache) Totals(from, to string) PeriodTotals {
+	t := PeriodTotals{TokensByModel: make(map[string]int)}
+	for _, d := range s.DailyActivity {
+		if d.Date >= from && d.Date <= to {
+			t.Sessions += d.SessionCount
+			t.Messages += d.MessageCount
+			t.ToolCalls += d.ToolCallCount
+		}
+	}
+	for _, d := range s.DailyModelTokens {
+		if d.Date >= from && d.Date <= to {
+			for model, tokens := range d.TokensByModel {
+				t.TokensByModel[model] += tokens
+			}
+		}
+	}
+
+	if !s.HasProjectUsage() {
+		return t
+	}
+	t.Projects = make(map[string]int)
+	for dirName, pu := range s.ProjectUsage {
+		if Projects.Excluded(pu.Path, dirName) {
+			continue
+		}
+		name := ProjectName(pu.Path, dirName)
+		for date, byModel := range pu.Daily {
+			if date < from || date > to {
+				continue
+			}
+			for _, mu := range byModel {
+				t.Projects[name] += mu.TotalTokens()
+			}
+		}
+	}
+	return t
+}
+
+// OutputTokens sums output tokens across models
+func (t PeriodTotals) OutputTokens() int {
+	total := 0
+	for _, tokens := range t.TokensByModel {
+		total += tokens
+	}
+	return total
+}
@@ -0,0 +1,92 @@
+package store
+
+// Deduper tracks which JSONL lines and API responses have already been
+// counted. Claude Code writes one line per content block of a response, each
+// repeating the same message ID and usage, and resumed sessions copy earlier
+// history into a new file. Sharing one Deduper across all scanned files
+// counts each line and each response's usage exactly once.
+type Deduper struct {
+	lines  map[string]string // entry uuid -> session that first had it
+	usages map[string]string // message id + request id -> session
+	Report DedupReport
+}
+
+// DedupReport summarizes what a Deduper removed
+type DedupReport struct {
+	Lines            int                   // lines seen
+	DuplicateLines   int                   // lines copied from another file
+	UsageEntries     int                   // lines carrying usage
+	DuplicateUsage   int                   // repeated usage within one session
+	CrossFileUsage   int                   // repeated usage first seen in another file
+	Removed          map[string]ModelUsage // tokens dropped, keyed by model
+	RemovedBySession map[string]int        // total tokens dropped per session
+}
+
+// NewDeduper returns an empty Deduper
+func NewDeduper() *Deduper {
+	return &Deduper{
+		lines:  make(map[string]string),
+		usages: make(map[string]string),
+		Report: DedupReport{
+			Removed:          make(map[string]ModelUsage),
+			RemovedBySession: make(map[string]int),
+		},
+	}
+}
+
+// seenLine reports whether a line with this uuid was already counted in a
+// different session file, recording it otherwise
+func (d *Deduper) seenLine(uuid, session string) bool {
+	d.Report.Lines++
+	if uuid == "" {
+		return false
+	}
+	first, ok := d.lines[uuid]
+	if !ok {
+		d.lines[uuid] = session
+		return false
+	}
+	if first == session {
+		return false
+	}
+	d.Report.DuplicateLines++
+	return true
+}
+
+// seenUsage reports whether usage for this message/request pair was already
+// counted, recording it otherwise. Usage on a duplicate line is always a
+// repeat; otherwise entries without any ID are never deduplicated.
+func (d *Deduper) seenUsage(dupLine bool, messageID, requestID, session string) bool {
+	d.Report.UsageEntries++
+	if dupLine {
+		d.Report.CrossFileUsage++
+		return true
+	}
+	if messageID == "" && requestID == "" {
+		return false
+	}
+	key := messageID + ":" + requestID
+	first, ok := d.usages[key]
+	if !ok {
+		d.usages[key] = session
+		return false
+	}
+	if first == session {
+		d.Report.DuplicateUsage++
+	} else {
+		d.Report.CrossFileUsage++
+	}
+	return true
+}
+
+// removed records tokens that were dropped as duplicates
+func (d *Deduper) removed(model, session string, u *Usage) {
+	mu := d.Report.Removed[model]
+	mu.InputTokens += u.InputTokens
+	mu.OutputTokens += u.OutputTokens
+	mu.CacheReadInputTokens += u.CacheReadInputTokens
+	mu.CacheCreationInputTokens += u.CacheCreationInputTokens
+	d.Report.Removed[model] = mu
+	d.Report.RemovedBySession[session] += u.InputTokens + u.OutputTokens +
+		u.CacheReadInputTokens + u.CacheCreationInputTokens
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeduperSeenLine(t *testing.T) {
+	d := NewDeduper()
+	steps := []struct {
+		uuid, session string
+		want          bool
+	}{
+		{"a", "s1", false},
+		{"a", "s1", false}, // repeated within the same file
+		{"a", "s2", true},  // copied into a resumed session
+		{"b", "s2", false},
+		{"", "s1", false}, // lines without a uuid are never duplicates
+		{"", "s2", false},
+	}
+	for i, s := range steps {
+		if got := d.seenLine(s.uuid, s.session); got != s.want {
+			t.Errorf("step %d: seenLine(%q, %q) = %v, want %v", i, s.uuid, s.session, got, s.want)
+		}
+	}
+	if d.Report.Lines != len(steps) || d.Report.DuplicateLines != 1 {
+		t.Errorf("report = %d lines, %d duplicate; want %d, 1", d.Report.Lines, d.Report.DuplicateLines, len(steps))
+	}
+}
+
+func TestDeduperSeenUsage(t *testing.T) {
+	d := NewDeduper()
+	steps := []struct {
+		dupLine           bool
+		msg, req, session string
+		want              bool
+	}{
+		{false, "m1", "r1", "s1", false},
+		{false, "m1", "r1", "s1", true}, // next content block of the same response
+		{false, "m1", "r2", "s1", false},
+		{false, "m1", "r1", "s2", true}, // same response in another file
+		{false, "", "", "s1", false},    // no IDs: always counted
+		{false, "", "", "s1", false},
+		{true, "m9", "r9", "s2", true}, // usage on a copied line
+	}
+	for i, s := range steps {
+		if got := d.seenUsage(s.dupLine, s.msg, s.req, s.session); got != s.want {
+			t.Errorf("step %d: seenUsage(%v, %q, %q, %q) = %v, want %v", i, s.dupLine, s.msg, s.req, s.session, got, s.want)
+		}
+	}
+	r := d.Report
+	if r.UsageEntries != 7 || r.DuplicateUsage != 1 || r.CrossFileUsage != 2 {
+		t.Errorf("report = %d entries, %d duplicate, %d cross-file; want 7, 1, 2", r.UsageEntries, r.DuplicateUsage, r.CrossFileUsage)
+	}
+}
+
+func TestScanSessionStatsDedup(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, lines ...string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	user := `{"type":"user","uuid":"u1","timestamp":"2026-01-05T10:00:00Z","message":{"role":"user","content":"hi"}}`
+	// Two content blocks of one response, each repeating its usage
+	asst1 := `{"type":"assistant","uuid":"a1","requestId":"r1","timestamp":"2026-01-05T10:00:05Z","message":{"id":"m1","model":"claude-x","usage":{"input_tokens":10,"output_tokens":100}}}`
+	asst2 := `{"type":"assistant","uuid":"a2","requestId":"r1","timestamp":"2026-01-05T10:00:06Z","message":{"id":"m1","model":"claude-x","usage":{"input_tokens":10,"output_tokens":100}}}`
+	first := write("s1.jsonl", user, asst1, asst2)
+	// A resumed session repeats the earlier history before its own response
+	asst3 := `{"type":"assistant","uuid":"a3","requestId":"r2","timestamp":"2026-01-05T11:00:00Z","message":{"id":"m2","model":"claude-x","usage":{"input_tokens":5,"output_tokens":50}}}`
+	resumed := write("s2.jsonl", user, asst1, asst2, asst3)
+
+	d := NewDeduper()
+	ss1, err := ScanSessionStats(first, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss2, err := ScanSessionStats(resumed, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ss1.TokensOut["claude-x"]; got != 100 {
+		t.Errorf("first session output tokens = %d, want 100", got)
+	}
+	if got := ss2.TokensOut["claude-x"]; got != 50 {
+		t.Errorf("resumed session output tokens = %d, want 50", got)
+	}
+	if ss2.UserMessages != 0 || ss2.AsstMessages != 1 {
+		t.Errorf("resumed session messages = %d user, %d assistant; want 0, 1", ss2.UserMessages, ss2.AsstMessages)
+	}
+	if got := d.Report.Removed["claude-x"].OutputTokens; got != 300 {
+		t.Errorf("removed output tokens = %d, want 300", got)
+	}
+}
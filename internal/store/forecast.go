@@ -0,0 +1,111 @@
+package store
+
+import (
+	"math"
+	"time"
+)
+
+// ForecastWindow is how many complete days of history feed a forecast
+const ForecastWindow = 28
+
+// Forecast projects one metric to the end of a period
+type Forecast struct {
+	Actual  float64 // usage so far in the period
+	Linear  float64 // end-of-period total from a linear trend
+	Weekday float64 // end-of-period total from per-weekday averages
+	Low     float64 // lower end of the ~80% range
+	High    float64 // upper end of the ~80% range
+}
+
+// Estimate is the mean of both projections
+func (f Forecast) Estimate() float64 {
+	return (f.Linear + f.Weekday) / 2
+}
+
+// ForecastPeriod projects series to the end of the period [start, end) as
+// seen at now. Today counts as partly elapsed; the remaining days are
+// predicted from the ForecastWindow complete days before today.
+func ForecastPeriod(series DailySeries, start, end, now time.Time) Forecast {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var f Forecast
+	for d := start; d.Before(end) && !d.After(today); d = d.AddDate(0, 0, 1) {
+		f.Actual += series[d.Format("2006-01-02")]
+	}
+
+	// History: oldest first, index 0 is ForecastWindow days ago
+	history := make([]float64, ForecastWindow)
+	var byWeekday [7][]float64
+	for i := range history {
+		d := today.AddDate(0, 0, i-ForecastWindow)
+		history[i] = series[d.Format("2006-01-02")]
+		byWeekday[d.Weekday()] = append(byWeekday[d.Weekday()], history[i])
+	}
+
+	slope, intercept := linearFit(history)
+	var weekdayAvg [7]float64
+	for wd, vals := range byWeekday {
+		weekdayAvg[wd] = mean(vals)
+	}
+
+	// Remaining days, each weighted by the share still ahead of us
+	dayFrac := 1 - now.Sub(today).Hours()/24
+	remaining := 0.0
+	f.Linear, f.Weekday = f.Actual, f.Actual
+	for d := today; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if d.Before(start) {
+			continue
+		}
+		w := 1.0
+		if d.Equal(today) {
+			w = dayFrac
+		}
+		x := float64(ForecastWindow) + d.Sub(today).Hours()/24
+		f.Linear += w * math.Max(0, slope*x+intercept)
+		f.Weekday += w * weekdayAvg[d.Weekday()]
+		remaining += w
+	}
+
+	// Day-to-day spread around the weekday averages, summed over the
+	// remaining days as independent errors
+	var sq float64
+	for i, v := range history {
+		wd := today.AddDate(0, 0, i-ForecastWindow).Weekday()
+		sq += (v - weekdayAvg[wd]) * (v - weekdayAvg[wd])
+	}
+	spread := 1.28 * math.Sqrt(sq/float64(len(history))) * math.Sqrt(remaining)
+
+	f.Low = math.Max(f.Actual, math.Min(f.Linear, f.Weekday)-spread)
+	f.High = math.Max(f.Linear, f.Weekday) + spread
+	return f
+}
+
+// linearFit returns the least-squares line through (i, ys[i])
+func linearFit(ys []float64) (slope, intercept float64) {
+	n := float64(len(ys))
+	if n < 2 {
+		return 0, mean(ys)
+	}
+	var sx, sy, sxy, sxx float64
+	for i, y := range ys {
+		x := float64(i)
+		sx += x
+		sy += y
+		sxy += x * y
+		sxx += x * x
+	}
+	slope = (n*sxy - sx*sy) / (n*sxx - sx*sx)
+	intercept = (sy - slope*sx) / n
+	return slope, intercept
+}
+
+func mean(vals []float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range vals {
+		sum += v
+	}
+	return sum / float64(len(vals))
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// seriesFrom returns a series with value(i) on each of the n days before day
+func seriesFrom(day time.Time, n int, value func(i int) float64) DailySeries {
+	s := make(DailySeries)
+	for i := 0; i < n; i++ {
+		s[day.AddDate(0, 0, i-n).Format("2006-01-02")] = value(i)
+	}
+	return s
+}
+
+func TestForecastPeriod(t *testing.T) {
+	date := func(y int, m time.Month, d, h int) time.Time { return time.Date(y, m, d, h, 0, 0, 0, time.UTC) }
+	flat := func(int) float64 { return 10 }
+
+	wed := date(2026, 10, 14, 0)
+	week := seriesFrom(wed, ForecastWindow, flat)
+	week["2026-10-14"] = 5
+
+	nov := date(2026, 11, 1, 0)
+	month := seriesFrom(nov, 31, flat) // all of October
+
+	tests := []struct {
+		name                 string
+		series               DailySeries
+		start, end, now      time.Time
+		actual, linear, wday float64
+	}{
+		{
+			name:  "no history",
+			start: date(2026, 10, 12, 0), end: date(2026, 10, 19, 0), now: date(2026, 10, 14, 12),
+		},
+		{
+			// Mon and Tue at 10, half of Wed elapsed with 5, then Thu to Sun
+			name: "flat week, half of today left", series: week,
+			start: date(2026, 10, 12, 0), end: date(2026, 10, 19, 0), now: date(2026, 10, 14, 12),
+			actual: 25, linear: 25 + 5 + 40, wday: 25 + 5 + 40,
+		},
+		{
+			name: "today only", series: week,
+			start: wed, end: date(2026, 10, 15, 0), now: date(2026, 10, 14, 18),
+			actual: 5, linear: 5 + 2.5, wday: 5 + 2.5,
+		},
+		{
+			name: "last day of the month", series: seriesFrom(date(2026, 10, 31, 0), 30, flat),
+			start: date(2026, 10, 1, 0), end: nov, now: date(2026, 10, 31, 0),
+			actual: 300, linear: 310, wday: 310,
+		},
+		{
+			name: "period already over", series: month,
+			start: date(2026, 10, 1, 0), end: nov, now: date(2026, 11, 1, 9),
+			actual: 310, linear: 310, wday: 310,
+		},
+		{
+			// A rising trend projects past the weekday average of 10.5
+			name: "rising trend", series: seriesFrom(wed, ForecastWindow, func(i int) float64 { return float64(i) }),
+			start: wed, end: date(2026, 10, 15, 0), now: wed,
+			linear: 28, wday: 10.5,
+		},
+		{
+			// A falling trend is clipped at zero rather than going negative
+			name: "falling trend", series: seriesFrom(wed, ForecastWindow, func(i int) float64 { return float64(27 - i) }),
+			start: wed, end: date(2026, 10, 15, 0), now: wed,
+			linear: 0, wday: 16.5,
+		},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := ForecastPeriod(tt.series, tt.start, tt.end, tt.now)
+			if !near(f.Actual, tt.actual) || !near(f.Linear, tt.linear) || !near(f.Weekday, tt.wday) {
+				t.Errorf("actual, linear, weekday = %v, %v, %v; want %v, %v, %v",
+					f.Actual, f.Linear, f.Weekday, tt.actual, tt.linear, tt.wday)
+			}
+			if f.Low < f.Actual || f.Low > math.Min(f.Linear, f.Weekday) || f.High < math.Max(f.Linear, f.Weekday) {
+				t.Errorf("range %v..%v doesn't cover actual %v and projections %v, %v",
+					f.Low, f.High, f.Actual, f.Linear, f.Weekday)
+			}
+		})
+	}
+}
+
+func TestLinearFit(t *testing.T) {
+	tests := []struct {
+		ys               []float64
+		slope, intercept float64
+	}{
+		{nil, 0, 0},
+		{[]float64{4}, 0, 4},
+		{[]float64{1, 3, 5}, 2, 1},
+		{[]float64{7, 7, 7, 7}, 0, 7},
+	}
+	for _, tt := range tests {
+		slope, intercept := linearFit(tt.ys)
+		if math.Abs(slope-tt.slope) > 1e-9 || math.Abs(intercept-tt.intercept) > 1e-9 {
+			t.Errorf("linearFit(%v) = %v, %v, want %v, %v", tt.ys, slope, intercept, tt.slope, tt.intercept)
+		}
+	}
+}
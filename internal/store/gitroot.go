@@ -0,0 +1,170 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// gitRoots caches GitRoot lookups by path
+var gitRoots = make(map[string]string)
+
+// GitRoot returns the main working tree of the git repository containing
+// path, or "" if path is not inside one or no longer exists. Linked
+// worktrees, whose .git is a "gitdir:" file pointing into the main
+// repository's .git/worktrees, resolve to the main repository.
+func GitRoot(path string) string {
+	if path == "" {
+		return ""
+	}
+	if root, ok := gitRoots[path]; ok {
+		return root
+	}
+
+	// A removed project would otherwise resolve to a repository above it
+	if _, err := os.Stat(path); err != nil {
+		gitRoots[path] = ""
+		return ""
+	}
+
+	root := ""
+	for dir := filepath.Clean(path); ; dir = filepath.Dir(dir) {
+		info, err := os.Stat(filepath.Join(dir, ".git"))
+		if err == nil {
+			if info.IsDir() {
+				root = dir
+			} else {
+				root = worktreeMainRoot(dir)
+			}
+			break
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	gitRoots[path] = root
+	return root
+}
+
+// worktreeMainRoot follows the .git file of a linked worktree or submodule
+// at dir. Worktrees resolve to the main repository through the gitdir's
+// commondir file; submodules have none and are their own repository.
+func worktreeMainRoot(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, ".git"))
+	if err != nil {
+		return dir
+	}
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return dir
+	}
+	gitdir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(dir, gitdir)
+	}
+
+	common, err := os.ReadFile(filepath.Join(gitdir, "commondir"))
+	if err != nil {
+		return dir
+	}
+	commonDir := strings.TrimSpace(string(common))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitdir, commonDir)
+	}
+	commonDir = filepath.Clean(commonDir)
+	if filepath.Base(commonDir) != ".git" {
+		// Bare repository: name it after the git dir itself
+		return commonDir
+	}
+	return filepath.Dir(commonDir)
+}
+
+// GroupProjectsByRepo merges projects that live in the same git repository,
+// including its linked worktrees and subdirectories, into one Project per
+// repository with summed sessions, messages and token usage. Projects
+// outside any repository are kept as they are.
+func GroupProjectsByRepo(projects []Project) []Project {
+	return groupProjects(projects, func(p Project) string {
+		return GitRoot(p.Path)
+	})
+}
+
+// groupProjects merges projects with the same non-empty key into one
+// Project named after the key, listing the merged paths in Members
+func groupProjects(projects []Project, key func(Project) string) []Project {
+	byKey := make(map[string]*Project)
+	var order []string
+	for _, p := range projects {
+		k := key(p)
+		if k == "" {
+			k = p.Path
+			if k == "" {
+				k = p.DirName
+			}
+		}
+
+		g, ok := byKey[k]
+		if !ok {
+			g = &Project{DirName: p.DirName, Path: k, HasIndex: p.HasIndex}
+			byKey[k] = g
+			order = append(order, k)
+		}
+		g.Members = append(g.Members, ProjectMember{Path: p.Path, DirName: p.DirName})
+		if g.IndexErr == nil {
+			g.IndexErr = p.IndexErr
+		}
+		g.SessionCount += p.SessionCount
+		g.MessageCount += p.MessageCount
+		if p.LastActive.After(g.LastActive) {
+			g.LastActive = p.LastActive
+		}
+		if p.Usage != nil {
+			if g.Usage == nil {
+				g.Usage = &ProjectUsage{Path: k, Models: make(map[string]ModelUsage)}
+			}
+			mergeProjectUsage(g.Usage, p.Usage)
+		}
+	}
+
+	out := make([]Project, 0, len(order))
+	for _, k := range order {
+		g := byKey[k]
+		if len(g.Members) == 1 {
+			// A project alone under its key keeps its own identity
+			for _, p := range projects {
+				if p.DirName == g.Members[0].DirName {
+					g.DirName, g.MovedTo, g.OtherPaths = p.DirName, p.MovedTo, p.OtherPaths
+					break
+				}
+			}
+			if g.Members[0].Path == g.Path {
+				g.Members = nil
+			}
+		}
+		if len(g.Members) > 0 {
+			// Grouped rows are named by their key, not a member's dir
+			g.DirName = ""
+		}
+		sort.Slice(g.Members, func(i, j int) bool {
+			return g.Members[i].Path < g.Members[j].Path
+		})
+		out = append(out, *g)
+	}
+	return out
+}
+
+// mergeProjectUsage adds src's totals to dst, leaving out the per-day
+// breakdown
+func mergeProjectUsage(dst, src *ProjectUsage) {
+	dst.Sessions += src.Sessions
+	dst.Messages += src.Messages
+	for model, mu := range src.Models {
+		d := dst.Models[model]
+		d.InputTokens += mu.InputTokens
+		d.OutputTokens += mu.OutputTokens
+		d.CacheReadInputTokens += mu.CacheReadInputTokens
+		d.CacheCreationInputTokens += mu.CacheCreationInputTokens
+		dst.Models[model] = d
+	}
+}
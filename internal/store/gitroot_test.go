@@ -0,0 +1,34 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitRoot(t *testing.T) {
+	repo := t.TempDir()
+	for _, dir := range []string{".git", "app/src"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outside := t.TempDir()
+
+	tests := []struct {
+		name, path, want string
+	}{
+		{"repository root", repo, repo},
+		{"subdirectory", filepath.Join(repo, "app", "src"), repo},
+		{"deleted path under a repository", filepath.Join(repo, "removed", "sub"), ""},
+		{"outside any repository", outside, ""},
+		{"empty path", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GitRoot(tt.path); got != tt.want {
+				t.Errorf("GitRoot(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
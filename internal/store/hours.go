@@ -0,0 +1,52 @@
+package store
+
+import (
+	"time"
+)
+
+// HourMatrix counts messages by weekday (indexed by time.Weekday, so Sunday
+// is 0) and hour of day
+type HourMatrix [7][24]int
+
+// Total returns the number of messages in the matrix
+func (m *HourMatrix) Total() int {
+	total := 0
+	for _, row := range m {
+		for _, c := range row {
+			total += c
+		}
+	}
+	return total
+}
+
+// ComputeHourMatrix counts user and assistant messages at or after since by
+// weekday and hour in loc. An empty projectFilter includes all projects.
+func ComputeHourMatrix(since time.Time, loc *time.Location, projectFilter string) (*HourMatrix, error) {
+	projects, err := listProjectFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var m HourMatrix
+	for _, pf := range projects {
+		if !matchesProject(projectFilter, pf.Path, pf.DirName) {
+			continue
+		}
+
+		dedup := NewDeduper()
+		for _, path := range modifiedSince(pf.Files, since) {
+			ss, err := ScanSessionStats(path, dedup)
+			if err != nil {
+				continue
+			}
+			for _, ts := range ss.Timestamps {
+				if ts.Before(since) {
+					continue
+				}
+				local := ts.In(loc)
+				m[local.Weekday()][local.Hour()]++
+			}
+		}
+	}
+	return &m, nil
+}
@@ -2,40 +2,39 @@ package store
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
-
-	"github.com/dkd/ccs/internal/claude"
 )
 
-// LoadAllProjects scans all project directories and returns aggregated project info
+// LoadAllProjects scans all project directories, live and archived, and
+// returns aggregated project info
 func LoadAllProjects() ([]Project, error) {
-	projectsDir := claude.ProjectsDir()
-	entries, err := os.ReadDir(projectsDir)
+	defer saveSynthCache()
+	dirs, err := listProjectDirs()
 	if err != nil {
 		return nil, err
 	}
 
 	var projects []Project
-	for _, entry := range entries {
-		if !entry.IsDir() {
+	for _, pd := range dirs {
+		if pd.excluded() {
 			continue
 		}
-		dirName := entry.Name()
-		projDir := filepath.Join(projectsDir, dirName)
+		dirName := pd.DirName
 
 		p := Project{
 			DirName: dirName,
 		}
 
 		// Try to load sessions-index.json
-		indexPath := filepath.Join(projDir, "sessions-index.json")
-		if idx, err := loadSessionIndex(indexPath); err == nil {
-			p.HasIndex = true
+		if idx, err := pd.index(); err == nil {
+			p.HasIndex = !idx.Synthesized
+			p.IndexErr = idx.Err
 			p.Path = idx.OriginalPath
+			p.OtherPaths = idx.OtherPaths
 			p.SessionCount = len(idx.Entries)
 			for _, e := range idx.Entries {
 				p.MessageCount += e.MessageCount
@@ -49,7 +48,7 @@ func LoadAllProjects() ([]Project, error) {
 			// Fallback: count JSONL files and use mtime
 			// Don't attempt path conversion - dash encoding is ambiguous
 			p.Path = dirName
-			jsonlFiles, _ := filepath.Glob(filepath.Join(projDir, "*.jsonl"))
+			jsonlFiles := pd.sessionFiles()
 			p.SessionCount = len(jsonlFiles)
 			for _, f := range jsonlFiles {
 				if info, err := os.Stat(f); err == nil {
@@ -68,39 +67,27 @@ func LoadAllProjects() ([]Project, error) {
 		projects = append(projects, p)
 	}
 
+	detectMovedProjects(projects)
 	return projects, nil
 }
 
 // ListAllSessions returns all sessions across all projects, sorted by created desc
 func ListAllSessions(projectFilter string) ([]SessionEntry, error) {
-	projectsDir := claude.ProjectsDir()
-	entries, err := os.ReadDir(projectsDir)
+	defer saveSynthCache()
+	dirs, err := listProjectDirs()
 	if err != nil {
 		return nil, err
 	}
 
 	var allSessions []SessionEntry
-	for _, entry := range entries {
-		if !entry.IsDir() {
+	for _, pd := range dirs {
+		idx, err := pd.index()
+		if err != nil || Projects.Excluded(idx.OriginalPath, pd.DirName) {
 			continue
 		}
-		dirName := entry.Name()
-
-		// Apply project filter: match against dir name and originalPath from index
-		if projectFilter != "" {
-			filter := strings.ToLower(projectFilter)
-			if !strings.Contains(strings.ToLower(dirName), filter) {
-				// Also check sessions-index for originalPath match
-				idxPath := filepath.Join(projectsDir, dirName, "sessions-index.json")
-				if idx, err := loadSessionIndex(idxPath); err != nil || !strings.Contains(strings.ToLower(idx.OriginalPath), filter) {
-					continue
-				}
-			}
-		}
 
-		indexPath := filepath.Join(projectsDir, dirName, "sessions-index.json")
-		idx, err := loadSessionIndex(indexPath)
-		if err != nil {
+		// Apply project filter: match against dir name, originalPath, alias and group
+		if !matchesProject(projectFilter, idx.OriginalPath, pd.DirName) {
 			continue
 		}
 
@@ -133,43 +120,75 @@ func ListSessionsAfter(after time.Time) ([]SessionEntry, error) {
 	return filtered, nil
 }
 
-// FindSession finds a session by ID prefix match, returns path and entry
+// AmbiguousSessionError is returned by FindSession when an ID prefix
+// matches more than one session
+type AmbiguousSessionError struct {
+	Prefix  string
+	Matches []SessionEntry // newest first
+}
+
+func (e *AmbiguousSessionError) Error() string {
+	var ids []string
+	for i, m := range e.Matches {
+		if i == 5 {
+			ids = append(ids, "...")
+			break
+		}
+		ids = append(ids, m.SessionID)
+	}
+	return fmt.Sprintf("session ID %q is ambiguous: %d sessions match (%s)", e.Prefix, len(e.Matches), strings.Join(ids, ", "))
+}
+
+// FindSession finds a session by ID prefix match, returns path and entry.
+// A prefix matching several sessions yields an *AmbiguousSessionError.
 func FindSession(idPrefix string) (string, *SessionEntry, error) {
-	projectsDir := claude.ProjectsDir()
-	entries, err := os.ReadDir(projectsDir)
+	defer saveSynthCache()
+	dirs, err := listProjectDirs()
 	if err != nil {
 		return "", nil, err
 	}
 
 	idPrefix = strings.ToLower(idPrefix)
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		projDir := filepath.Join(projectsDir, entry.Name())
-
+	var matches []SessionEntry
+	hasEntry := make(map[string]bool) // session ID -> matched with index metadata
+	seen := make(map[string]bool)
+	for _, pd := range dirs {
 		// Check sessions-index.json first for metadata
-		indexPath := filepath.Join(projDir, "sessions-index.json")
-		if idx, err := loadSessionIndex(indexPath); err == nil {
-			for i, e := range idx.Entries {
-				if strings.HasPrefix(strings.ToLower(e.SessionID), idPrefix) {
-					return e.FullPath, &idx.Entries[i], nil
+		if idx, err := pd.index(); err == nil {
+			for _, e := range idx.Entries {
+				if strings.HasPrefix(strings.ToLower(e.SessionID), idPrefix) && !seen[e.SessionID] {
+					seen[e.SessionID] = true
+					hasEntry[e.SessionID] = true
+					matches = append(matches, e)
 				}
 			}
 		}
 
 		// Fallback: match JSONL filenames
-		jsonlFiles, _ := filepath.Glob(filepath.Join(projDir, "*.jsonl"))
-		for _, f := range jsonlFiles {
-			base := strings.TrimSuffix(filepath.Base(f), ".jsonl")
-			if strings.HasPrefix(strings.ToLower(base), idPrefix) {
-				return f, nil, nil
+		for _, f := range pd.sessionFiles() {
+			id := sessionIDFromPath(f)
+			if strings.HasPrefix(strings.ToLower(id), idPrefix) && !seen[id] {
+				seen[id] = true
+				matches = append(matches, SessionEntry{SessionID: id, FullPath: f})
 			}
 		}
 	}
 
-	return "", nil, os.ErrNotExist
+	switch len(matches) {
+	case 0:
+		return "", nil, os.ErrNotExist
+	case 1:
+		m := matches[0]
+		if !hasEntry[m.SessionID] {
+			return m.FullPath, nil, nil
+		}
+		return m.FullPath, &m, nil
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Modified > matches[j].Modified
+	})
+	return "", nil, &AmbiguousSessionError{Prefix: idPrefix, Matches: matches}
 }
 
 func loadSessionIndex(path string) (*SessionIndex, error) {
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeHome points HOME at an empty temp dir and resets the synthesized
+// session cache, so tests see only the transcripts they write
+func fakeHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	synth = nil
+	t.Cleanup(func() { synth = nil })
+	return home
+}
+
+// writeSession writes a one-message transcript for id under the fake
+// home's projects directory
+func writeSession(t *testing.T, home, project, id, timestamp string) {
+	t.Helper()
+	dir := filepath.Join(home, ".claude", "projects", project)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := fmt.Sprintf(`{"type":"user","sessionId":%q,"timestamp":%q,"cwd":"/x/proj","message":{"role":"user","content":"hi"}}`+"\n", id, timestamp)
+	if err := os.WriteFile(filepath.Join(dir, id+".jsonl"), []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindSession(t *testing.T) {
+	home := fakeHome(t)
+
+	writeSession(t, home, "-x-proj", "abc111", "2026-10-01T10:00:00Z")
+	writeSession(t, home, "-x-proj", "abc222", "2026-10-03T10:00:00Z")
+	writeSession(t, home, "-x-other", "abc333", "2026-10-02T10:00:00Z")
+	writeSession(t, home, "-x-other", "def444", "2026-10-02T10:00:00Z")
+
+	tests := []struct {
+		prefix    string
+		want      string   // session ID of a unique match
+		ambiguous []string // matches of an ambiguous prefix, newest first
+		notFound  bool
+	}{
+		{prefix: "abc1", want: "abc111"},
+		{prefix: "DEF", want: "def444"}, // prefixes are case-insensitive
+		{prefix: "abc", ambiguous: []string{"abc222", "abc333", "abc111"}},
+		{prefix: "", ambiguous: []string{"abc222", "abc333", "def444", "abc111"}},
+		{prefix: "zzz", notFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			path, entry, err := FindSession(tt.prefix)
+			var amb *AmbiguousSessionError
+			switch {
+			case tt.notFound:
+				if !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("FindSession(%q) error = %v, want os.ErrNotExist", tt.prefix, err)
+				}
+			case tt.ambiguous != nil:
+				if !errors.As(err, &amb) {
+					t.Fatalf("FindSession(%q) error = %v, want *AmbiguousSessionError", tt.prefix, err)
+				}
+				var ids []string
+				for _, m := range amb.Matches {
+					ids = append(ids, m.SessionID)
+				}
+				if fmt.Sprint(ids) != fmt.Sprint(tt.ambiguous) {
+					t.Errorf("FindSession(%q) matches = %v, want %v", tt.prefix, ids, tt.ambiguous)
+				}
+				if path != "" || entry != nil {
+					t.Errorf("FindSession(%q) = %q, %v with an ambiguous prefix, want no result", tt.prefix, path, entry)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("FindSession(%q) error = %v", tt.prefix, err)
+				}
+				if sessionIDFromPath(path) != tt.want {
+					t.Errorf("FindSession(%q) path = %q, want session %s", tt.prefix, path, tt.want)
+				}
+				if entry == nil || entry.SessionID != tt.want {
+					t.Errorf("FindSession(%q) entry = %+v, want session %s", tt.prefix, entry, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAmbiguousSessionErrorListsFiveIDs(t *testing.T) {
+	err := &AmbiguousSessionError{Prefix: "a"}
+	for _, id := range []string{"a1", "a2", "a3", "a4", "a5", "a6", "a7"} {
+		err.Matches = append(err.Matches, SessionEntry{SessionID: id})
+	}
+	want := `session ID "a" is ambiguous: 7 sessions match (a1, a2, a3, a4, a5, ...)`
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/dkd/ccs/internal/config"
+)
+
+// Projects holds the aliases, exclusions and groups from config.json.
+// Excluded projects are left out wherever projects or sessions are listed,
+// but still count towards totals such as the stats cache and 5h blocks.
+var Projects config.ProjectSettings
+
+// ProjectName returns a project's display name: its alias if one is
+// configured, otherwise its path, otherwise its directory name
+func ProjectName(path, dirName string) string {
+	if alias := Projects.Alias(path, dirName); alias != "" {
+		return alias
+	}
+	if path != "" {
+		return path
+	}
+	return dirName
+}
+
+// matchesProject reports whether filter occurs (case-insensitive) in a
+// project's directory name, path, alias or group. An empty filter matches
+// every project.
+func matchesProject(filter, path, dirName string) bool {
+	if filter == "" {
+		return true
+	}
+	filter = strings.ToLower(filter)
+	for _, s := range []string{dirName, path, Projects.Alias(path, dirName), Projects.Group(path, dirName)} {
+		if s != "" && strings.Contains(strings.ToLower(s), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupProjectsByConfig merges projects into the named groups of
+// config.json. Projects in no group keep their own row.
+func GroupProjectsByConfig(projects []Project) []Project {
+	return groupProjects(projects, func(p Project) string {
+		return Projects.Group(p.Path, p.DirName)
+	})
+}
@@ -0,0 +1,46 @@
+package store
+
+import "strings"
+
+// Price is the API list price in USD per million tokens
+type Price struct {
+	Input      float64
+	Output     float64
+	CacheWrite float64
+	CacheRead  float64
+}
+
+// PriceFor returns the list price for a model ID. Unknown models are priced
+// like Sonnet, the most common default.
+func PriceFor(model string) Price {
+	switch {
+	case strings.Contains(model, "opus-4-5"), strings.Contains(model, "opus-4-6"):
+		return Price{Input: 5, Output: 25, CacheWrite: 6.25, CacheRead: 0.50}
+	case strings.Contains(model, "opus"):
+		return Price{Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.50}
+	case strings.Contains(model, "haiku-4-5"):
+		return Price{Input: 1, Output: 5, CacheWrite: 1.25, CacheRead: 0.10}
+	case strings.Contains(model, "haiku"):
+		return Price{Input: 0.80, Output: 4, CacheWrite: 1, CacheRead: 0.08}
+	default:
+		return Price{Input: 3, Output: 15, CacheWrite: 3.75, CacheRead: 0.30}
+	}
+}
+
+// EstimateCost returns the estimated API cost in USD of usage on model
+func EstimateCost(model string, u ModelUsage) float64 {
+	p := PriceFor(model)
+	return (float64(u.InputTokens)*p.Input +
+		float64(u.OutputTokens)*p.Output +
+		float64(u.CacheCreationInputTokens)*p.CacheWrite +
+		float64(u.CacheReadInputTokens)*p.CacheRead) / 1_000_000
+}
+
+// EstimateCostByModel sums the estimated cost of per-model usage
+func EstimateCostByModel(models map[string]ModelUsage) float64 {
+	total := 0.0
+	for model, u := range models {
+		total += EstimateCost(model, u)
+	}
+	return total
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"os"
+	"slices"
+	"sort"
+)
+
+// resolveProjectPath picks a project's path by majority vote over the
+// working directories its sessions started in, since the dash-encoded
+// directory name can't be decoded reliably. cwds maps session IDs to the
+// cwd recorded in their transcripts, which wins over an entry's
+// projectPath. Ties go to the path of the most recently modified session.
+// It also returns the other paths seen, most votes first; fallback is used
+// when no session recorded a cwd.
+func resolveProjectPath(entries []SessionEntry, cwds map[string]string, fallback string) (string, []string) {
+	votes := make(map[string]int)
+	latest := make(map[string]string) // path -> newest Modified
+	for _, e := range entries {
+		path := cwds[e.SessionID]
+		if path == "" {
+			path = e.ProjectPath
+		}
+		if path == "" {
+			continue
+		}
+		votes[path]++
+		if e.Modified > latest[path] {
+			latest[path] = e.Modified
+		}
+	}
+	if len(votes) == 0 {
+		return fallback, nil
+	}
+
+	paths := make([]string, 0, len(votes))
+	for p := range votes {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		a, b := paths[i], paths[j]
+		if votes[a] != votes[b] {
+			return votes[a] > votes[b]
+		}
+		if latest[a] != latest[b] {
+			return latest[a] > latest[b]
+		}
+		return a < b
+	})
+	return paths[0], paths[1:]
+}
+
+// detectMovedProjects sets MovedTo on projects whose path no longer exists
+// when a project that exists on disk and was active later has sessions
+// that ran in the old path, which is what resuming a session after
+// renaming or moving a checkout leaves behind
+func detectMovedProjects(projects []Project) {
+	exists := make([]bool, len(projects))
+	for i, p := range projects {
+		if p.Path == "" || p.Path == p.DirName {
+			continue
+		}
+		if _, err := os.Stat(p.Path); err == nil {
+			exists[i] = true
+		}
+	}
+
+	for i := range projects {
+		p := &projects[i]
+		if exists[i] || p.Path == "" || p.Path == p.DirName {
+			continue
+		}
+		best := -1
+		for j, q := range projects {
+			if !exists[j] || !q.LastActive.After(p.LastActive) || !slices.Contains(q.OtherPaths, p.Path) {
+				continue
+			}
+			if best < 0 || q.LastActive.After(projects[best].LastActive) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			p.MovedTo = projects[best].Path
+		}
+	}
+}
@@ -5,12 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
-
-	"github.com/dkd/ccs/internal/claude"
 )
 
 // SessionStats holds lightweight stats extracted from a session JSONL file.
@@ -21,29 +18,94 @@ type SessionStats struct {
 	UserMessages int
 	AsstMessages int
 	ToolCalls    int
+	Tools        map[string]int // tool name -> calls
+	EditedFiles  map[string]int // file path -> Edit/Write calls
 	TokensIn     map[string]int // keyed by model
 	TokensOut    map[string]int
 	CacheRead    map[string]int
 	CacheCreate  map[string]int
 	Model        string
+	GitBranch    string                  // last branch seen
+	Branches     map[string]*BranchUsage // keyed by the entries' own gitBranch
+	Timestamps   []time.Time             // user and assistant entries, in file order
+	Usage        []UsageEvent            // de-duplicated assistant usage, in file order
+}
+
+// BranchUsage is the part of a session spent on one git branch. Each gap
+// between consecutive messages belongs to the branch of the later one, so
+// the branches of a session add up to its totals.
+type BranchUsage struct {
+	Messages  int
+	TokensIn  int
+	TokensOut int
+	Gaps      []time.Duration
+}
+
+// WallDuration sums the branch's gaps between messages
+func (b *BranchUsage) WallDuration() time.Duration {
+	var d time.Duration
+	for _, g := range b.Gaps {
+		d += g
+	}
+	return d
+}
+
+// ActiveDuration sums the branch's gaps of at most IdleGap
+func (b *BranchUsage) ActiveDuration() time.Duration {
+	var d time.Duration
+	for _, g := range b.Gaps {
+		if g <= IdleGap {
+			d += g
+		}
+	}
+	return d
+}
+
+// UsageEvent is the usage of a single API response
+type UsageEvent struct {
+	Timestamp time.Time
+	Model     string
+	Usage     ModelUsage
 }
 
 // ScanSessionStats does a lightweight parse of a session JSONL file,
 // extracting only stats (tokens, model, tool counts, timestamps) without
 // building full message content or the Messages slice.
-func ScanSessionStats(path string) (*SessionStats, error) {
-	file, err := os.Open(path)
+// Repeated lines and usage are skipped via dedup; pass a shared Deduper to
+// de-duplicate across files, or nil to de-duplicate within this file only.
+func ScanSessionStats(path string, dedup *Deduper) (_ *SessionStats, err error) {
+	file, err := openSessionFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	defer func() {
+		// Decompressors report corrupt or truncated input when closed
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
 
 	ss := &SessionStats{
-		SessionID:   strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+		SessionID:   sessionIDFromPath(path),
+		Tools:       make(map[string]int),
+		EditedFiles: make(map[string]int),
 		TokensIn:    make(map[string]int),
 		TokensOut:   make(map[string]int),
 		CacheRead:   make(map[string]int),
 		CacheCreate: make(map[string]int),
+		Branches:    make(map[string]*BranchUsage),
+	}
+	branch := func() *BranchUsage {
+		b := ss.Branches[ss.GitBranch]
+		if b == nil {
+			b = &BranchUsage{}
+			ss.Branches[ss.GitBranch] = b
+		}
+		return b
+	}
+
+	if dedup == nil {
+		dedup = NewDeduper()
 	}
 
 	scanner := bufio.NewScanner(file)
@@ -63,20 +125,40 @@ func ScanSessionStats(path string) (*SessionStats, error) {
 			continue
 		}
 
-		if entry.Timestamp != "" {
+		// Lines copied from an earlier session only contribute usage
+		// bookkeeping, never messages, tools or timestamps
+		dupLine := dedup.seenLine(entry.UUID, ss.SessionID)
+		if dupLine && entry.Type != "assistant" {
+			continue
+		}
+
+		if !dupLine && entry.GitBranch != "" {
+			ss.GitBranch = entry.GitBranch
+		}
+		if !dupLine && entry.Timestamp != "" {
 			if ts, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
 				if firstTs.IsZero() {
 					firstTs = ts
 				}
 				lastTs = ts
+				if entry.Type == "user" || entry.Type == "assistant" {
+					if n := len(ss.Timestamps); n > 0 && ts.After(ss.Timestamps[n-1]) {
+						branch().Gaps = append(branch().Gaps, ts.Sub(ss.Timestamps[n-1]))
+					}
+					ss.Timestamps = append(ss.Timestamps, ts)
+				}
 			}
 		}
 
 		switch entry.Type {
 		case "user":
 			ss.UserMessages++
+			branch().Messages++
 		case "assistant":
-			ss.AsstMessages++
+			if !dupLine {
+				ss.AsstMessages++
+				branch().Messages++
+			}
 			if entry.Message == nil {
 				continue
 			}
@@ -87,19 +169,43 @@ func ScanSessionStats(path string) (*SessionStats, error) {
 			}
 
 			model := msgContent.Model
-			if model != "" {
+			if model != "" && !dupLine {
 				ss.Model = model
 			}
 
 			if msgContent.Usage != nil {
-				m := ss.Model
+				m := model
+				if m == "" {
+					m = ss.Model
+				}
 				if m == "" {
 					m = "unknown"
 				}
-				ss.TokensIn[m] += msgContent.Usage.InputTokens
-				ss.TokensOut[m] += msgContent.Usage.OutputTokens
-				ss.CacheRead[m] += msgContent.Usage.CacheReadInputTokens
-				ss.CacheCreate[m] += msgContent.Usage.CacheCreationInputTokens
+				if dedup.seenUsage(dupLine, msgContent.ID, entry.RequestID, ss.SessionID) {
+					dedup.removed(m, ss.SessionID, msgContent.Usage)
+				} else {
+					ss.TokensIn[m] += msgContent.Usage.InputTokens
+					ss.TokensOut[m] += msgContent.Usage.OutputTokens
+					branch().TokensIn += msgContent.Usage.InputTokens
+					branch().TokensOut += msgContent.Usage.OutputTokens
+					ss.CacheRead[m] += msgContent.Usage.CacheReadInputTokens
+					ss.CacheCreate[m] += msgContent.Usage.CacheCreationInputTokens
+					if ts, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
+						ss.Usage = append(ss.Usage, UsageEvent{
+							Timestamp: ts,
+							Model:     m,
+							Usage: ModelUsage{
+								InputTokens:              msgContent.Usage.InputTokens,
+								OutputTokens:             msgContent.Usage.OutputTokens,
+								CacheReadInputTokens:     msgContent.Usage.CacheReadInputTokens,
+								CacheCreationInputTokens: msgContent.Usage.CacheCreationInputTokens,
+							},
+						})
+					}
+				}
+			}
+			if dupLine {
+				continue
 			}
 
 			// Count tool_use blocks without extracting text
@@ -109,6 +215,10 @@ func ScanSessionStats(path string) (*SessionStats, error) {
 					for _, b := range blocks {
 						if b.Type == "tool_use" {
 							ss.ToolCalls++
+							ss.Tools[b.Name]++
+							if f := editedFile(b); f != "" {
+								ss.EditedFiles[f]++
+							}
 						}
 					}
 				}
@@ -122,25 +232,61 @@ func ScanSessionStats(path string) (*SessionStats, error) {
 	return ss, scanner.Err()
 }
 
+// editedFile returns the file a file-modifying tool call writes to, if any
+func editedFile(b ContentBlock) string {
+	switch b.Name {
+	case "Edit", "MultiEdit", "Write", "NotebookEdit":
+	default:
+		return ""
+	}
+	input, ok := b.Input.(map[string]any)
+	if !ok {
+		return ""
+	}
+	for _, key := range []string{"file_path", "notebook_path"} {
+		if f, ok := input[key].(string); ok {
+			return f
+		}
+	}
+	return ""
+}
+
 // ComputeStats scans all session JSONL files and builds a fresh StatsCache.
-// The progress callback is called after each session is scanned.
-func ComputeStats(progress func(done, total int)) (*StatsCache, error) {
-	projectsDir := claude.ProjectsDir()
-	entries, err := os.ReadDir(projectsDir)
+// The progress callback is called after each session is scanned. Files that
+// can't be read, e.g. compressed ones without a decompressor or with a
+// corrupt stream, are left out of the totals and returned as skipped.
+func ComputeStats(progress func(done, total int)) (stats *StatsCache, skipped []error, err error) {
+	return computeStats(progress, NewDeduper())
+}
+
+// VerifyStats computes stats like ComputeStats and also returns the report
+// of duplicate lines and usage that were left out of the totals.
+func VerifyStats(progress func(done, total int)) (*StatsCache, *DedupReport, error) {
+	dedup := NewDeduper()
+	stats, _, err := computeStats(progress, dedup)
 	if err != nil {
-		return nil, fmt.Errorf("reading projects dir: %w", err)
+		return nil, nil, err
+	}
+	return stats, &dedup.Report, nil
+}
+
+func computeStats(progress func(done, total int), dedup *Deduper) (*StatsCache, []error, error) {
+	projects, err := listProjectFiles()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Collect all JSONL file paths
+	// Collect all JSONL file paths, oldest first so copied history is
+	// attributed to the session it came from
 	var allFiles []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	fileProject := make(map[string]*projectFiles)
+	for i := range projects {
+		allFiles = append(allFiles, projects[i].Files...)
+		for _, f := range projects[i].Files {
+			fileProject[f] = &projects[i]
 		}
-		projDir := filepath.Join(projectsDir, entry.Name())
-		files, _ := filepath.Glob(filepath.Join(projDir, "*.jsonl"))
-		allFiles = append(allFiles, files...)
 	}
+	sortByMtime(allFiles)
 
 	total := len(allFiles)
 
@@ -149,18 +295,21 @@ func ComputeStats(progress func(done, total int)) (*StatsCache, error) {
 	dailyTokens := make(map[string]map[string]int)  // date -> model -> output tokens
 	modelUsage := make(map[string]*ModelUsage)       // model -> usage
 	hourCounts := make(map[string]int)               // hour string -> count
+	projectUsage := make(map[string]ProjectUsage)    // project dir -> usage
 
 	var totalSessions, totalMessages int
 	var firstSession string
 	var longest LongestSession
+	var skipped []error
 
 	for i, path := range allFiles {
 		if progress != nil {
 			progress(i+1, total)
 		}
 
-		ss, err := ScanSessionStats(path)
+		ss, err := ScanSessionStats(path, dedup)
 		if err != nil {
+			skipped = append(skipped, fmt.Errorf("%s: %w", path, err))
 			continue
 		}
 
@@ -223,15 +372,51 @@ func ComputeStats(progress func(done, total int)) (*StatsCache, error) {
 			mu.CacheCreationInputTokens += ss.CacheCreate[model]
 		}
 
-		// Longest session
+		// Per-project totals
+		if pf := fileProject[path]; pf != nil {
+			pu, ok := projectUsage[pf.DirName]
+			if !ok {
+				pu = ProjectUsage{
+					Path:   pf.Path,
+					Models: make(map[string]ModelUsage),
+					Daily:  make(map[string]map[string]ModelUsage),
+				}
+			}
+			pu.Sessions++
+			pu.Messages += msgCount
+			for model, out := range ss.TokensOut {
+				mu := pu.Models[model]
+				mu.OutputTokens += out
+				mu.InputTokens += ss.TokensIn[model]
+				mu.CacheReadInputTokens += ss.CacheRead[model]
+				mu.CacheCreationInputTokens += ss.CacheCreate[model]
+				pu.Models[model] = mu
+
+				if date != "" {
+					if pu.Daily[date] == nil {
+						pu.Daily[date] = make(map[string]ModelUsage)
+					}
+					du := pu.Daily[date][model]
+					du.OutputTokens += out
+					du.InputTokens += ss.TokensIn[model]
+					du.CacheReadInputTokens += ss.CacheRead[model]
+					du.CacheCreationInputTokens += ss.CacheCreate[model]
+					pu.Daily[date][model] = du
+				}
+			}
+			projectUsage[pf.DirName] = pu
+		}
+
+		// Longest session, ranked by active time so idle sessions don't win
 		if !ss.StartedAt.IsZero() && !ss.EndedAt.IsZero() {
-			dur := ss.EndedAt.Sub(ss.StartedAt).Milliseconds()
-			if dur > longest.Duration {
+			active, wall := ss.ActiveDuration().Milliseconds(), ss.WallDuration().Milliseconds()
+			if longest.outranks(active, wall) {
 				longest = LongestSession{
-					SessionID:    ss.SessionID,
-					Duration:     dur,
-					MessageCount: msgCount,
-					Timestamp:    ss.StartedAt.Format(time.RFC3339),
+					SessionID:      ss.SessionID,
+					Duration:       wall,
+					ActiveDuration: active,
+					MessageCount:   msgCount,
+					Timestamp:      ss.StartedAt.Format(time.RFC3339),
 				}
 			}
 		}
@@ -280,7 +465,80 @@ func ComputeStats(progress func(done, total int)) (*StatsCache, error) {
 		LongestSession:   longest,
 		FirstSessionDate: firstSession,
 		HourCounts:       hourCounts,
+		ProjectUsage:     projectUsage,
 	}
 
-	return stats, nil
+	return stats, skipped, nil
+}
+
+// projectFiles lists the session files of one project directory
+type projectFiles struct {
+	DirName  string
+	Path     string // originalPath from index, or the dir name
+	Files    []string
+	Excluded bool // hidden by config.json; counted in totals, not listed
+}
+
+// listProjectFiles returns the session files of every project directory,
+// including sessions that only survive in the archive
+func listProjectFiles() ([]projectFiles, error) {
+	defer saveSynthCache()
+	dirs, err := listProjectDirs()
+	if err != nil {
+		return nil, fmt.Errorf("reading projects dir: %w", err)
+	}
+
+	var projects []projectFiles
+	for _, pd := range dirs {
+		pf := projectFiles{DirName: pd.DirName, Path: pd.DirName}
+		if idx, err := pd.index(); err == nil && idx.OriginalPath != "" {
+			pf.Path = idx.OriginalPath
+		}
+		pf.Excluded = Projects.Excluded(pf.Path, pd.DirName)
+		pf.Files = pd.sessionFiles()
+		projects = append(projects, pf)
+	}
+	return projects, nil
+}
+
+// sortByMtime sorts file paths by modification time, oldest first
+func sortByMtime(files []string) {
+	mtimes := make(map[string]time.Time, len(files))
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			mtimes[f] = info.ModTime()
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return mtimes[files[i]].Before(mtimes[files[j]])
+	})
+}
+
+// outranks reports whether a session with the given active and wall-clock
+// milliseconds is longer than l. Active time ranks first and wall-clock time
+// breaks ties, so a cache of single-message or idle sessions still has a
+// longest session; any session outranks an empty l.
+func (l LongestSession) outranks(active, wall int64) bool {
+	switch {
+	case l.SessionID == "":
+		return true
+	case active != l.ActiveDuration:
+		return active > l.ActiveDuration
+	default:
+		return wall > l.Duration
+	}
+}
+
+// modifiedSince returns the files last written at or after since, oldest
+// first. Files last written before since can't contain messages after it.
+func modifiedSince(files []string, since time.Time) []string {
+	var out []string
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil && info.ModTime().Before(since) {
+			continue
+		}
+		out = append(out, f)
+	}
+	sortByMtime(out)
+	return out
 }
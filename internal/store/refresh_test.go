@@ -0,0 +1,39 @@
+package store
+
+import "testing"
+
+func TestLongestSessionOutranks(t *testing.T) {
+	tests := []struct {
+		name         string
+		longest      LongestSession
+		active, wall int64
+		want         bool
+	}{
+		{"any session beats none", LongestSession{}, 0, 0, true},
+		{"more active time wins", LongestSession{SessionID: "a", ActiveDuration: 10, Duration: 500}, 20, 20, true},
+		{"less active time loses", LongestSession{SessionID: "a", ActiveDuration: 10, Duration: 10}, 5, 900, false},
+		{"wall time breaks a tie", LongestSession{SessionID: "a", ActiveDuration: 0, Duration: 100}, 0, 200, true},
+		{"equal sessions keep the first", LongestSession{SessionID: "a", ActiveDuration: 0, Duration: 100}, 0, 100, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.longest.outranks(tt.active, tt.wall); got != tt.want {
+				t.Errorf("%+v.outranks(%d, %d) = %v, want %v", tt.longest, tt.active, tt.wall, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeStatsLongestSingleMessageSession(t *testing.T) {
+	home := fakeHome(t)
+	writeSession(t, home, "-x-proj", "only1", "2026-10-01T10:00:00Z")
+	writeSession(t, home, "-x-proj", "only2", "2026-10-02T10:00:00Z")
+
+	stats, _, err := ComputeStats(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.LongestSession.SessionID == "" {
+		t.Errorf("LongestSession is empty for a cache of single-message sessions")
+	}
+}
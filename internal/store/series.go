@@ -0,0 +1,99 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// DailySeries maps YYYY-MM-DD to a daily value
+type DailySeries map[string]float64
+
+// DailyMessages returns messages per day
+func (s *StatsCache) DailyMessages() DailySeries {
+	out := make(DailySeries)
+	for _, d := range s.DailyActivity {
+		out[d.Date] += float64(d.MessageCount)
+	}
+	return out
+}
+
+// DailySessions returns sessions started per day
+func (s *StatsCache) DailySessions() DailySeries {
+	out := make(DailySeries)
+	for _, d := range s.DailyActivity {
+		out[d.Date] += float64(d.SessionCount)
+	}
+	return out
+}
+
+// DailyOutputTokens returns output tokens per day across models
+func (s *StatsCache) DailyOutputTokens() DailySeries {
+	out := make(DailySeries)
+	for _, d := range s.DailyModelTokens {
+		for _, t := range d.TokensByModel {
+			out[d.Date] += float64(t)
+		}
+	}
+	return out
+}
+
+// DailyCost returns the estimated cost per day, or nil if the cache lacks
+// per-project usage
+func (s *StatsCache) DailyCost() DailySeries {
+	if !s.HasProjectUsage() {
+		return nil
+	}
+	out := make(DailySeries)
+	for _, pu := range s.ProjectUsage {
+		for date, byModel := range pu.Daily {
+			out[date] += EstimateCostByModel(byModel)
+		}
+	}
+	return out
+}
+
+// Streaks returns the current and longest runs of consecutive days with a
+// non-zero value. The current streak stays alive until a full day is missed,
+// so it counts back from yesterday if today has no activity yet.
+func (s DailySeries) Streaks(today time.Time) (current, longest int) {
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	if s[day.Format("2006-01-02")] <= 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for s[day.Format("2006-01-02")] > 0 {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	longest, _, _ = s.LongestStreak()
+	return current, longest
+}
+
+// LongestStreak returns the longest run of consecutive days with a non-zero
+// value and its first and last date; the earliest run wins a tie
+func (s DailySeries) LongestStreak() (days int, start, end string) {
+	var dates []string
+	for date, v := range s {
+		if v > 0 {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	run, runStart := 0, ""
+	var prev time.Time
+	for _, date := range dates {
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		if run == 0 || d.Sub(prev) != 24*time.Hour {
+			run, runStart = 0, date
+		}
+		run++
+		prev = d
+		if run > days {
+			days, start, end = run, runStart, date
+		}
+	}
+	return days, start, end
+}
@@ -3,22 +3,25 @@ package store
 import (
 	"bufio"
 	"encoding/json"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 )
 
 // ParseSessionJSONL parses a full session JSONL file into a SessionDetail
-func ParseSessionJSONL(path string) (*SessionDetail, error) {
-	file, err := os.Open(path)
+func ParseSessionJSONL(path string) (_ *SessionDetail, err error) {
+	file, err := openSessionFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	defer func() {
+		// Decompressors report corrupt or truncated input when closed
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
 
 	detail := &SessionDetail{
-		ID:    strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+		ID:    sessionIDFromPath(path),
 		Tools: make(map[string]*ToolStats),
 	}
 
@@ -28,6 +31,7 @@ func ParseSessionJSONL(path string) (*SessionDetail, error) {
 
 	var firstTs, lastTs time.Time
 	msgSeq := 0
+	dedup := NewDeduper()
 
 	for scanner.Scan() {
 		line := scanner.Bytes()
@@ -139,8 +143,8 @@ func ParseSessionJSONL(path string) (*SessionDetail, error) {
 				}
 			}
 
-			// Track tokens and model
-			if msgContent.Usage != nil {
+			// Track tokens and model, counting each API response once
+			if msgContent.Usage != nil && !dedup.seenUsage(false, msgContent.ID, entry.RequestID, detail.ID) {
 				detail.TotalTokensIn += msgContent.Usage.InputTokens
 				detail.TotalTokensOut += msgContent.Usage.OutputTokens
 			}
@@ -0,0 +1,217 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/config"
+)
+
+// Snapshot is a dated copy of a computed StatsCache together with the
+// sessions it was computed from
+type Snapshot struct {
+	Date     string            `json:"date"`
+	TakenAt  time.Time         `json:"takenAt"`
+	Stats    StatsCache        `json:"stats"`
+	Sessions map[string]string `json:"sessions"` // session ID -> project dir
+}
+
+// SnapshotInfo describes a stored snapshot without loading it
+type SnapshotInfo struct {
+	Date string
+	Path string
+	Size int64
+}
+
+// SnapshotDir returns the directory holding one snapshot file per day
+func SnapshotDir() string {
+	return filepath.Join(config.DataDir(), "snapshots")
+}
+
+// SaveSnapshot stores stats and the current session list as today's
+// snapshot, replacing an earlier one from the same day
+func SaveSnapshot(stats *StatsCache) (string, error) {
+	sessions, err := currentSessions()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	snap := Snapshot{
+		Date:     now.Format("2006-01-02"),
+		TakenAt:  now,
+		Stats:    *stats,
+		Sessions: sessions,
+	}
+	data, err := json.Marshal(&snap)
+	if err != nil {
+		return "", fmt.Errorf("marshaling snapshot: %w", err)
+	}
+	path := filepath.Join(SnapshotDir(), snap.Date+".json")
+	if err := writeFileAtomic(path, data); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// CurrentSnapshot builds an unsaved snapshot from the current stats cache
+// and session files
+func CurrentSnapshot() (*Snapshot, error) {
+	stats, err := LoadStatsCache()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := currentSessions()
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{
+		Date:     "current",
+		TakenAt:  time.Now(),
+		Stats:    *stats,
+		Sessions: sessions,
+	}, nil
+}
+
+// currentSessions maps every session ID, live or archived, to its project
+func currentSessions() (map[string]string, error) {
+	projects, err := listProjectFiles()
+	if err != nil {
+		return nil, err
+	}
+	sessions := make(map[string]string)
+	for _, pf := range projects {
+		for _, f := range pf.Files {
+			sessions[sessionIDFromPath(f)] = pf.DirName
+		}
+	}
+	return sessions, nil
+}
+
+// ListSnapshots returns the stored snapshots, oldest first
+func ListSnapshots() ([]SnapshotInfo, error) {
+	entries, err := os.ReadDir(SnapshotDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var out []SnapshotInfo
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, SnapshotInfo{
+			Date: strings.TrimSuffix(name, ".json"),
+			Path: filepath.Join(SnapshotDir(), name),
+			Size: info.Size(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Date < out[j].Date
+	})
+	return out, nil
+}
+
+// LoadSnapshot loads a snapshot by date (YYYY-MM-DD), "latest" for the most
+// recent stored one, or "current" for the live stats cache
+func LoadSnapshot(ref string) (*Snapshot, error) {
+	if ref == "current" {
+		return CurrentSnapshot()
+	}
+	if ref == "latest" {
+		list, err := ListSnapshots()
+		if err != nil {
+			return nil, err
+		}
+		if len(list) == 0 {
+			return nil, fmt.Errorf("no snapshots yet, run `ccs refresh`")
+		}
+		ref = list[len(list)-1].Date
+	}
+	if _, err := time.Parse("2006-01-02", ref); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %q (use YYYY-MM-DD, latest or current)", ref)
+	}
+
+	path := filepath.Join(SnapshotDir(), ref+".json")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no snapshot for %q (see `ccs snapshots list`)", ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &snap, nil
+}
+
+// SnapshotDiff is what changed from snapshot A to snapshot B
+type SnapshotDiff struct {
+	Sessions     int               // change in TotalSessions
+	Messages     int               // change in TotalMessages
+	OutputTokens map[string]int    // model -> change in output tokens
+	Added        []string          // session IDs only in B
+	Removed      map[string]string // session IDs only in A -> project dir
+	ShrunkDays   map[string][2]int // date -> messages in A and B, where B has fewer
+}
+
+// DiffSnapshots compares two snapshots. Days whose message count went down
+// and removed sessions both point at pruned transcripts.
+func DiffSnapshots(a, b *Snapshot) *SnapshotDiff {
+	d := &SnapshotDiff{
+		Sessions:     b.Stats.TotalSessions - a.Stats.TotalSessions,
+		Messages:     b.Stats.TotalMessages - a.Stats.TotalMessages,
+		OutputTokens: make(map[string]int),
+		Removed:      make(map[string]string),
+		ShrunkDays:   make(map[string][2]int),
+	}
+
+	for model, mu := range b.Stats.ModelUsage {
+		d.OutputTokens[model] += mu.OutputTokens
+	}
+	for model, mu := range a.Stats.ModelUsage {
+		d.OutputTokens[model] -= mu.OutputTokens
+	}
+	for model, delta := range d.OutputTokens {
+		if delta == 0 {
+			delete(d.OutputTokens, model)
+		}
+	}
+
+	for id := range b.Sessions {
+		if _, ok := a.Sessions[id]; !ok {
+			d.Added = append(d.Added, id)
+		}
+	}
+	sort.Strings(d.Added)
+	for id, project := range a.Sessions {
+		if _, ok := b.Sessions[id]; !ok {
+			d.Removed[id] = project
+		}
+	}
+
+	after := make(map[string]int)
+	for _, day := range b.Stats.DailyActivity {
+		after[day.Date] = day.MessageCount
+	}
+	for _, day := range a.Stats.DailyActivity {
+		if after[day.Date] < day.MessageCount {
+			d.ShrunkDays[day.Date] = [2]int{day.MessageCount, after[day.Date]}
+		}
+	}
+	return d
+}
@@ -0,0 +1,350 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/dkd/ccs/internal/claude"
+	"github.com/dkd/ccs/internal/config"
+)
+
+// projectDir is one project directory as seen in the live data dir, the
+// archive, or both
+type projectDir struct {
+	DirName string
+	Live    string // path under ~/.claude/projects, "" if Claude Code removed it
+	Archive string // path under the archive, "" if never archived
+}
+
+// archiveProjectsDir returns the archive's mirror of ~/.claude/projects
+func archiveProjectsDir() string {
+	return filepath.Join(config.ArchiveDir(), "projects")
+}
+
+// listProjectDirs returns the union of live and archived project
+// directories, sorted by name. Projects excluded in config.json are
+// included, so totals stay complete; listings skip them with excluded().
+func listProjectDirs() ([]projectDir, error) {
+	byName := make(map[string]*projectDir)
+	add := func(root string, archived bool) error {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			pd := byName[entry.Name()]
+			if pd == nil {
+				pd = &projectDir{DirName: entry.Name()}
+				byName[entry.Name()] = pd
+			}
+			if archived {
+				pd.Archive = filepath.Join(root, entry.Name())
+			} else {
+				pd.Live = filepath.Join(root, entry.Name())
+			}
+		}
+		return nil
+	}
+
+	liveErr := add(claude.ProjectsDir(), false)
+	add(archiveProjectsDir(), true)
+	if liveErr != nil && len(byName) == 0 {
+		return nil, liveErr
+	}
+
+	dirs := make([]projectDir, 0, len(byName))
+	for _, pd := range byName {
+		dirs = append(dirs, *pd)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].DirName < dirs[j].DirName
+	})
+	return dirs, nil
+}
+
+// excluded reports whether config.json hides the project, matching its
+// dir name first so the index is only read when a path pattern might match
+func (pd projectDir) excluded() bool {
+	if len(Projects.Exclude) == 0 {
+		return false
+	}
+	if Projects.Excluded("", pd.DirName) {
+		return true
+	}
+	idx, err := pd.index()
+	return err == nil && Projects.Excluded(idx.OriginalPath, pd.DirName)
+}
+
+// sessionFiles returns every live session file of the project, plus the
+// archived copies of sessions that are no longer in the live directory
+func (pd projectDir) sessionFiles() []string {
+	var files []string
+	live := make(map[string]bool)
+	if pd.Live != "" {
+		for _, f := range globSessionFiles(pd.Live) {
+			files = append(files, f)
+			live[sessionIDFromPath(f)] = true
+		}
+	}
+	if pd.Archive != "" {
+		for _, f := range globSessionFiles(pd.Archive) {
+			if !live[sessionIDFromPath(f)] {
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
+
+// index returns the project's sessions-index.json, with entries from the
+// archived index added for sessions the live index no longer lists. Entries
+// whose transcript was compressed or only survives in the archive point at
+// the file that exists. Sessions no index lists, including every session
+// of a project without an index, get entries synthesized from their
+// transcripts; an index that exists but can't be read is reported in Err.
+// The synthesized entries are cached in memory, so callers listing projects
+// save the cache once with saveSynthCache when they are done.
+func (pd projectDir) index() (*SessionIndex, error) {
+	merged := &SessionIndex{Version: 1}
+	load := func(dir string) *SessionIndex {
+		if dir == "" {
+			return nil
+		}
+		path := filepath.Join(dir, "sessions-index.json")
+		idx, err := loadSessionIndex(path)
+		if err != nil && !os.IsNotExist(err) && merged.Err == nil {
+			merged.Err = fmt.Errorf("reading %s: %w", path, err)
+		}
+		return idx
+	}
+	live, archived := load(pd.Live), load(pd.Archive)
+
+	switch {
+	case live != nil:
+		merged.Version, merged.OriginalPath = live.Version, live.OriginalPath
+	case archived != nil:
+		merged.Version, merged.OriginalPath = archived.Version, archived.OriginalPath
+	default:
+		merged.Synthesized = true
+	}
+
+	seen := make(map[string]bool)
+	for _, idx := range []*SessionIndex{live, archived} {
+		if idx == nil {
+			continue
+		}
+		for _, e := range idx.Entries {
+			if !seen[e.SessionID] {
+				seen[e.SessionID] = true
+				merged.Entries = append(merged.Entries, e)
+			}
+		}
+	}
+
+	sessionFiles := pd.sessionFiles()
+	files := make(map[string]string)
+	for _, f := range sessionFiles {
+		files[sessionIDFromPath(f)] = f
+	}
+	for i, e := range merged.Entries {
+		if _, err := os.Stat(e.FullPath); err != nil {
+			if f, ok := files[e.SessionID]; ok {
+				merged.Entries[i].FullPath = f
+			}
+		}
+	}
+
+	// Every transcript is read (once, then cached) for the cwd it recorded,
+	// which the path vote prefers over the index's projectPath
+	cache := loadSynthCache()
+	cwds := make(map[string]string)
+	for _, f := range sessionFiles {
+		e, ok := cache.entry(f)
+		if !ok {
+			continue
+		}
+		cwds[e.SessionID] = e.ProjectPath
+		if !seen[e.SessionID] {
+			merged.Entries = append(merged.Entries, e)
+		}
+	}
+
+	merged.OriginalPath, merged.OtherPaths = resolveProjectPath(merged.Entries, cwds, merged.OriginalPath)
+	for i, e := range merged.Entries {
+		if e.ProjectPath == "" {
+			merged.Entries[i].ProjectPath = merged.OriginalPath
+		}
+	}
+
+	if merged.Synthesized && len(merged.Entries) == 0 {
+		return nil, os.ErrNotExist
+	}
+	return merged, nil
+}
+
+// sessionFileExts are the transcript file extensions ccs reads, preferred
+// in this order when a session exists in more than one form
+var sessionFileExts = []string{".jsonl", ".jsonl.gz", ".jsonl.zst"}
+
+// globSessionFiles returns the session transcripts in dir, one per session.
+// A session that is both plain and compressed (e.g. while being compressed)
+// is returned as the plain file.
+func globSessionFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	best := make(map[string]int) // session ID -> index into files
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !isSessionFile(e.Name()) {
+			continue
+		}
+		id := sessionIDFromPath(e.Name())
+		path := filepath.Join(dir, e.Name())
+		if i, ok := best[id]; ok {
+			if sessionFileRank(path) < sessionFileRank(files[i]) {
+				files[i] = path
+			}
+			continue
+		}
+		best[id] = len(files)
+		files = append(files, path)
+	}
+	return files
+}
+
+func sessionFileRank(path string) int {
+	for i, ext := range sessionFileExts {
+		if strings.HasSuffix(path, ext) {
+			return i
+		}
+	}
+	return len(sessionFileExts)
+}
+
+// isSessionFile reports whether name is a plain or compressed session
+// transcript
+func isSessionFile(name string) bool {
+	for _, ext := range sessionFileExts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionIDFromPath returns the session ID encoded in a transcript's file name
+func sessionIDFromPath(path string) string {
+	base := filepath.Base(path)
+	for _, ext := range sessionFileExts {
+		if strings.HasSuffix(base, ext) {
+			return strings.TrimSuffix(base, ext)
+		}
+	}
+	return base
+}
+
+// openSessionFile opens a session transcript, decompressing .gz files
+// in-process and .zst files through the zstd command
+func openSessionFile(path string) (io.ReadCloser, error) {
+	switch {
+	case strings.HasSuffix(path, ".zst"):
+		return openZstd(path)
+	case strings.HasSuffix(path, ".gz"):
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &gzipFile{Reader: zr, file: f}, nil
+	default:
+		return os.Open(path)
+	}
+}
+
+// gzipFile closes both the decompressor and the underlying file
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+// openZstd streams path through `zstd -dc`, as the standard library has no
+// zstd decoder
+func openZstd(path string) (io.ReadCloser, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	zstd, err := exec.LookPath("zstd")
+	if err != nil {
+		return nil, errors.New("zstd not found in PATH")
+	}
+	c := exec.Command(zstd, "-dcq", path)
+	stderr := &bytes.Buffer{}
+	c.Stderr = stderr
+	out, err := c.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	return &zstdFile{ReadCloser: out, cmd: c, stderr: stderr}, nil
+}
+
+// zstdFile reaps the zstd process when closed and reports its failure, so
+// a corrupt or truncated file isn't mistaken for a short one
+type zstdFile struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+	eof    bool
+}
+
+func (z *zstdFile) Read(p []byte) (int, error) {
+	n, err := z.ReadCloser.Read(p)
+	if err == io.EOF {
+		z.eof = true
+	}
+	return n, err
+}
+
+func (z *zstdFile) Close() error {
+	z.ReadCloser.Close()
+	err := z.cmd.Wait()
+	if err == nil {
+		return nil
+	}
+	// Closing the pipe early makes zstd exit on SIGPIPE, which is expected
+	var exitErr *exec.ExitError
+	if !z.eof && errors.As(err, &exitErr) {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() && ws.Signal() == syscall.SIGPIPE {
+			return nil
+		}
+	}
+	if msg := strings.TrimSpace(z.stderr.String()); msg != "" {
+		return fmt.Errorf("zstd: %s", msg)
+	}
+	return fmt.Errorf("zstd: %w", err)
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTruncatedZstdIsReported(t *testing.T) {
+	zstd, err := exec.LookPath("zstd")
+	if err != nil {
+		t.Skip("zstd not in PATH")
+	}
+
+	dir := t.TempDir()
+	var lines strings.Builder
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&lines, `{"type":"user","uuid":"u%d","timestamp":"2026-10-01T10:%02d:00Z","message":{"role":"user","content":"message %d"}}`+"\n", i, i%60, i)
+	}
+	plain := filepath.Join(dir, "s.jsonl")
+	if err := os.WriteFile(plain, []byte(lines.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	good := filepath.Join(dir, "good.jsonl.zst")
+	if out, err := exec.Command(zstd, "-q", plain, "-o", good).CombinedOutput(); err != nil {
+		t.Fatalf("zstd: %v: %s", err, out)
+	}
+	data, err := os.ReadFile(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := filepath.Join(dir, "truncated.jsonl.zst")
+	if err := os.WriteFile(truncated, data[:len(data)/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseSessionJSONL(good); err != nil {
+		t.Errorf("ParseSessionJSONL(good) error = %v", err)
+	}
+	if _, err := synthesizeEntry(good); err != nil {
+		t.Errorf("synthesizeEntry(good) error = %v", err)
+	}
+	if _, err := ScanSessionStats(good, NewDeduper()); err != nil {
+		t.Errorf("ScanSessionStats(good) error = %v", err)
+	}
+
+	if _, err := ParseSessionJSONL(truncated); err == nil {
+		t.Error("ParseSessionJSONL(truncated) error = nil, want the zstd failure")
+	}
+	if _, err := synthesizeEntry(truncated); err == nil {
+		t.Error("synthesizeEntry(truncated) error = nil, want the zstd failure")
+	}
+	if _, err := ScanSessionStats(truncated, NewDeduper()); err == nil {
+		t.Error("ScanSessionStats(truncated) error = nil, want the zstd failure")
+	}
+}
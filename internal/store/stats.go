@@ -36,3 +36,62 @@ func SaveStatsCache(stats *StatsCache) error {
 	}
 	return nil
 }
+
+// AttachProjectUsage sets Usage on each project from the stats cache's
+// per-project totals, if the cache has them
+func AttachProjectUsage(projects []Project, stats *StatsCache) {
+	if stats == nil {
+		return
+	}
+	for i := range projects {
+		if pu, ok := stats.ProjectUsage[projects[i].DirName]; ok {
+			projects[i].Usage = &pu
+		}
+	}
+}
+
+// OutputTokensSince sums output tokens across models from DailyModelTokens
+// for dates on or after since (YYYY-MM-DD)
+func (s *StatsCache) OutputTokensSince(since string) int {
+	total := 0
+	for _, d := range s.DailyModelTokens {
+		if d.Date >= since {
+			for _, t := range d.TokensByModel {
+				total += t
+			}
+		}
+	}
+	return total
+}
+
+// HasProjectUsage reports whether the cache carries ccs's per-project data
+func (s *StatsCache) HasProjectUsage() bool {
+	return len(s.ProjectUsage) > 0
+}
+
+// ProjectUsageSince sums per-model usage for dates on or after since from
+// projects whose path, dir name, alias or group contains match
+// (case-insensitive). An empty match includes every project, like the
+// user-wide totals; a non-empty one skips projects excluded in config.json.
+func (s *StatsCache) ProjectUsageSince(since, match string) map[string]ModelUsage {
+	out := make(map[string]ModelUsage)
+	for dirName, pu := range s.ProjectUsage {
+		if (match != "" && Projects.Excluded(pu.Path, dirName)) || !matchesProject(match, pu.Path, dirName) {
+			continue
+		}
+		for date, byModel := range pu.Daily {
+			if date < since {
+				continue
+			}
+			for model, mu := range byModel {
+				t := out[model]
+				t.InputTokens += mu.InputTokens
+				t.OutputTokens += mu.OutputTokens
+				t.CacheReadInputTokens += mu.CacheReadInputTokens
+				t.CacheCreationInputTokens += mu.CacheCreationInputTokens
+				out[model] = t
+			}
+		}
+	}
+	return out
+}
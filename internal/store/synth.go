@@ -0,0 +1,205 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dkd/ccs/internal/config"
+)
+
+// synthPromptLen caps the first prompt kept in synthesized entries
+const synthPromptLen = 200
+
+// synthCache holds SessionEntry records built from transcripts, keyed by
+// file path and reused while the file's size and mtime are unchanged
+type synthCache struct {
+	Version int                    `json:"version"`
+	Files   map[string]synthCached `json:"files"`
+	dirty   bool
+}
+
+type synthCached struct {
+	Size  int64        `json:"size"`
+	Mtime int64        `json:"mtime"` // unix ms
+	Entry SessionEntry `json:"entry"`
+}
+
+// synth is loaded on first use and shared by every index() call
+var synth *synthCache
+
+func synthCachePath() string {
+	return filepath.Join(config.CacheDir(), "sessions.json")
+}
+
+func loadSynthCache() *synthCache {
+	if synth != nil {
+		return synth
+	}
+	synth = &synthCache{Version: 1, Files: make(map[string]synthCached)}
+	data, err := os.ReadFile(synthCachePath())
+	if err != nil {
+		return synth
+	}
+	var c synthCache
+	if json.Unmarshal(data, &c) == nil && c.Version == synth.Version && c.Files != nil {
+		synth.Files = c.Files
+	}
+	return synth
+}
+
+// entry returns the synthesized entry for path, scanning the file only if
+// it changed since it was cached
+func (c *synthCache) entry(path string) (SessionEntry, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return SessionEntry{}, false
+	}
+	mtime := info.ModTime().UnixMilli()
+	if cached, ok := c.Files[path]; ok && cached.Size == info.Size() && cached.Mtime == mtime {
+		return cached.Entry, cached.Entry.MessageCount > 0
+	}
+
+	e, err := synthesizeEntry(path)
+	if err != nil {
+		return SessionEntry{}, false
+	}
+	e.FileMtime = mtime
+	c.Files[path] = synthCached{Size: info.Size(), Mtime: mtime, Entry: e}
+	c.dirty = true
+	return e, e.MessageCount > 0
+}
+
+// saveSynthCache saves the shared cache, if it was loaded. Functions that
+// list projects defer it, so the cache is written once per listing rather
+// than once per project.
+func saveSynthCache() {
+	if synth != nil {
+		synth.save()
+	}
+}
+
+// save writes the cache if anything was scanned. Entries for files that no
+// longer exist are dropped.
+func (c *synthCache) save() {
+	if !c.dirty {
+		return
+	}
+	for path := range c.Files {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(c.Files, path)
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if writeFileAtomic(synthCachePath(), data) == nil {
+		c.dirty = false
+	}
+}
+
+// synthesizeEntry builds the sessions-index.json entry Claude Code would
+// have written for a transcript
+func synthesizeEntry(path string) (_ SessionEntry, err error) {
+	file, err := openSessionFile(path)
+	if err != nil {
+		return SessionEntry{}, err
+	}
+	defer func() {
+		// Decompressors report corrupt or truncated input when closed
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	e := SessionEntry{
+		SessionID: sessionIDFromPath(path),
+		FullPath:  path,
+	}
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 256*1024)
+	scanner.Buffer(buf, 10*1024*1024)
+
+	var first, last time.Time
+	seenEntry := false
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry RawEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		if entry.Type != "user" && entry.Type != "assistant" {
+			continue
+		}
+
+		if !seenEntry {
+			e.IsSidechain = entry.IsSidechain
+			seenEntry = true
+		}
+		e.MessageCount++
+		if ts, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
+			if first.IsZero() {
+				first = ts
+			}
+			last = ts
+		}
+		if e.GitBranch == "" {
+			e.GitBranch = entry.GitBranch
+		}
+		if e.ProjectPath == "" {
+			e.ProjectPath = entry.CWD
+		}
+		if e.FirstPrompt == "" && entry.Type == "user" {
+			e.FirstPrompt = promptText(entry.Message)
+		}
+	}
+
+	if !first.IsZero() {
+		e.Created = first.UTC().Format(time.RFC3339)
+		e.Modified = last.UTC().Format(time.RFC3339)
+	}
+	if e.FirstPrompt == "" {
+		e.FirstPrompt = "No prompt"
+	}
+	return e, scanner.Err()
+}
+
+// promptText returns the text a user typed, or "" for tool results
+func promptText(raw json.RawMessage) string {
+	if raw == nil {
+		return ""
+	}
+	var msg MessageContent
+	if err := json.Unmarshal(raw, &msg); err != nil || len(msg.Content) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(msg.Content, &text); err != nil {
+		var blocks []ContentBlock
+		if err := json.Unmarshal(msg.Content, &blocks); err != nil {
+			return ""
+		}
+		var parts []string
+		for _, b := range blocks {
+			if b.Type == "text" && b.Text != "" {
+				parts = append(parts, b.Text)
+			}
+		}
+		text = strings.Join(parts, " ")
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > synthPromptLen {
+		text = string(r[:synthPromptLen]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,163 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bundle is an anonymized per-user export of a StatsCache. It carries only
+// aggregated counters — no session IDs, prompts or project paths.
+type Bundle struct {
+	Version    int        `json:"version"`
+	User       string     `json:"user"`
+	ExportedAt string     `json:"exportedAt"`
+	Stats      StatsCache `json:"stats"`
+}
+
+// NewBundle builds an anonymized bundle for user from the given stats
+func NewBundle(user string, stats *StatsCache) *Bundle {
+	anon := *stats
+	anon.LongestSession.SessionID = ""
+	anon.ProjectUsage = nil
+	return &Bundle{
+		Version:    1,
+		User:       user,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Stats:      anon,
+	}
+}
+
+// SaveBundle writes a bundle as indented JSON to path
+func SaveBundle(b *Bundle, path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling bundle: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadBundles reads all *.json bundles from dir, sorted by user name.
+// Files that don't parse as bundles are skipped.
+func LoadBundles(dir string) ([]Bundle, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var bundles []Bundle
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var b Bundle
+		if err := json.Unmarshal(data, &b); err != nil || b.Version == 0 {
+			continue
+		}
+		if b.User == "" {
+			b.User = strings.TrimSuffix(filepath.Base(f), ".json")
+		}
+		bundles = append(bundles, b)
+	}
+
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].User < bundles[j].User
+	})
+	return bundles, nil
+}
+
+// MergeStats combines several stats caches into one, summing daily activity,
+// daily tokens, model usage and hour counts.
+func MergeStats(caches []*StatsCache) *StatsCache {
+	dailyAct := make(map[string]*DailyActivity)
+	dailyTokens := make(map[string]map[string]int)
+	merged := &StatsCache{
+		Version:    1,
+		ModelUsage: make(map[string]ModelUsage),
+		HourCounts: make(map[string]int),
+	}
+
+	for _, s := range caches {
+		merged.TotalSessions += s.TotalSessions
+		merged.TotalMessages += s.TotalMessages
+
+		if s.LastComputedDate > merged.LastComputedDate {
+			merged.LastComputedDate = s.LastComputedDate
+		}
+		if s.FirstSessionDate != "" && (merged.FirstSessionDate == "" || s.FirstSessionDate < merged.FirstSessionDate) {
+			merged.FirstSessionDate = s.FirstSessionDate
+		}
+		if longestKey(s.LongestSession) > longestKey(merged.LongestSession) {
+			merged.LongestSession = s.LongestSession
+		}
+
+		for _, d := range s.DailyActivity {
+			da, ok := dailyAct[d.Date]
+			if !ok {
+				da = &DailyActivity{Date: d.Date}
+				dailyAct[d.Date] = da
+			}
+			da.MessageCount += d.MessageCount
+			da.SessionCount += d.SessionCount
+			da.ToolCallCount += d.ToolCallCount
+		}
+
+		for _, d := range s.DailyModelTokens {
+			if dailyTokens[d.Date] == nil {
+				dailyTokens[d.Date] = make(map[string]int)
+			}
+			for model, tokens := range d.TokensByModel {
+				dailyTokens[d.Date][model] += tokens
+			}
+		}
+
+		for model, mu := range s.ModelUsage {
+			m := merged.ModelUsage[model]
+			m.InputTokens += mu.InputTokens
+			m.OutputTokens += mu.OutputTokens
+			m.CacheReadInputTokens += mu.CacheReadInputTokens
+			m.CacheCreationInputTokens += mu.CacheCreationInputTokens
+			merged.ModelUsage[model] = m
+		}
+
+		for h, c := range s.HourCounts {
+			merged.HourCounts[h] += c
+		}
+	}
+
+	for _, da := range dailyAct {
+		merged.DailyActivity = append(merged.DailyActivity, *da)
+	}
+	sort.Slice(merged.DailyActivity, func(i, j int) bool {
+		return merged.DailyActivity[i].Date < merged.DailyActivity[j].Date
+	})
+
+	for date, byModel := range dailyTokens {
+		merged.DailyModelTokens = append(merged.DailyModelTokens, DailyModelTokens{
+			Date:          date,
+			TokensByModel: byModel,
+		})
+	}
+	sort.Slice(merged.DailyModelTokens, func(i, j int) bool {
+		return merged.DailyModelTokens[i].Date < merged.DailyModelTokens[j].Date
+	})
+
+	return merged
+}
+
+// longestKey ranks a longest session by active time, falling back to wall
+// time for caches written before active time was tracked
+func longestKey(ls LongestSession) int64 {
+	if ls.ActiveDuration > 0 {
+		return ls.ActiveDuration
+	}
+	return ls.Duration
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// WorkBlock is a continuous stretch of activity in one project
+type WorkBlock struct {
+	Project  string
+	Start    time.Time
+	End      time.Time
+	Sessions int
+}
+
+// Duration returns the length of the block
+func (b WorkBlock) Duration() time.Duration {
+	return b.End.Sub(b.Start)
+}
+
+// TimesheetDay is the active time spent on one project on one local date
+type TimesheetDay struct {
+	Date     string
+	Project  string
+	Duration time.Duration
+}
+
+// ComputeWorkBlocks collects message timestamps in [since, until) per project,
+// merges overlapping sessions and splits wherever consecutive messages are
+// more than idle apart. Blocks are sorted by start time.
+func ComputeWorkBlocks(since, until time.Time, idle time.Duration, projectFilter string) ([]WorkBlock, error) {
+	projects, err := listProjectFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []WorkBlock
+	for _, pf := range projects {
+		if pf.Excluded || !matchesProject(projectFilter, pf.Path, pf.DirName) {
+			continue
+		}
+		name := ProjectName(pf.Path, pf.DirName)
+
+		type mark struct {
+			ts      time.Time
+			session int
+		}
+		var marks []mark
+		dedup := NewDeduper()
+		for i, path := range modifiedSince(pf.Files, since) {
+			ss, err := ScanSessionStats(path, dedup)
+			if err != nil {
+				continue
+			}
+			for _, ts := range ss.Timestamps {
+				if !ts.Before(since) && ts.Before(until) {
+					marks = append(marks, mark{ts, i})
+				}
+			}
+		}
+		if len(marks) == 0 {
+			continue
+		}
+
+		sort.Slice(marks, func(i, j int) bool {
+			return marks[i].ts.Before(marks[j].ts)
+		})
+
+		cur := WorkBlock{Project: name, Start: marks[0].ts, End: marks[0].ts}
+		seen := map[int]bool{marks[0].session: true}
+		for _, m := range marks[1:] {
+			if m.ts.Sub(cur.End) > idle {
+				cur.Sessions = len(seen)
+				blocks = append(blocks, cur)
+				cur = WorkBlock{Project: name, Start: m.ts, End: m.ts}
+				seen = make(map[int]bool)
+			}
+			cur.End = m.ts
+			seen[m.session] = true
+		}
+		cur.Sessions = len(seen)
+		blocks = append(blocks, cur)
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Start.Before(blocks[j].Start)
+	})
+	return blocks, nil
+}
+
+// TimesheetDays sums work blocks per local date and project, splitting blocks
+// that cross midnight. The result is sorted by date, then project.
+func TimesheetDays(blocks []WorkBlock, loc *time.Location) []TimesheetDay {
+	type key struct{ date, project string }
+	totals := make(map[key]time.Duration)
+
+	for _, b := range blocks {
+		start := b.Start.In(loc)
+		end := b.End.In(loc)
+		for start.Before(end) {
+			midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+			stop := end
+			if midnight.Before(end) {
+				stop = midnight
+			}
+			totals[key{start.Format("2006-01-02"), b.Project}] += stop.Sub(start)
+			start = stop
+		}
+	}
+
+	var days []TimesheetDay
+	for k, d := range totals {
+		days = append(days, TimesheetDay{Date: k.date, Project: k.project, Duration: d})
+	}
+	sort.Slice(days, func(i, j int) bool {
+		if days[i].Date != days[j].Date {
+			return days[i].Date < days[j].Date
+		}
+		return days[i].Project < days[j].Project
+	})
+	return days
+}
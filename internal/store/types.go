@@ -17,6 +17,8 @@ type StatsCache struct {
 	LongestSession   LongestSession   `json:"longestSession"`
 	FirstSessionDate string           `json:"firstSessionDate"`
 	HourCounts       map[string]int   `json:"hourCounts"`
+	// ProjectUsage is written by ccs only, keyed by project directory name
+	ProjectUsage map[string]ProjectUsage `json:"projectUsage,omitempty"`
 }
 
 type DailyActivity struct {
@@ -38,11 +40,38 @@ type ModelUsage struct {
 	CacheCreationInputTokens int `json:"cacheCreationInputTokens"`
 }
 
+// ProjectUsage holds token totals and model mix for one project directory
+type ProjectUsage struct {
+	Path     string                           `json:"path"`
+	Sessions int                              `json:"sessions"`
+	Messages int                              `json:"messages"`
+	Models   map[string]ModelUsage            `json:"models"`
+	Daily    map[string]map[string]ModelUsage `json:"daily,omitempty"` // date -> model -> usage
+}
+
+// Total sums usage across all models
+func (p ProjectUsage) Total() ModelUsage {
+	var t ModelUsage
+	for _, mu := range p.Models {
+		t.InputTokens += mu.InputTokens
+		t.OutputTokens += mu.OutputTokens
+		t.CacheReadInputTokens += mu.CacheReadInputTokens
+		t.CacheCreationInputTokens += mu.CacheCreationInputTokens
+	}
+	return t
+}
+
+// TotalTokens returns input, output and cache tokens combined
+func (m ModelUsage) TotalTokens() int {
+	return m.InputTokens + m.OutputTokens + m.CacheReadInputTokens + m.CacheCreationInputTokens
+}
+
 type LongestSession struct {
-	SessionID    string `json:"sessionId"`
-	Duration     int64  `json:"duration"`
-	MessageCount int    `json:"messageCount"`
-	Timestamp    string `json:"timestamp"`
+	SessionID      string `json:"sessionId"`
+	Duration       int64  `json:"duration"`                 // wall-clock ms
+	ActiveDuration int64  `json:"activeDuration,omitempty"` // ms without idle gaps
+	MessageCount   int    `json:"messageCount"`
+	Timestamp      string `json:"timestamp"`
 }
 
 // SessionIndex represents a project's sessions-index.json
@@ -50,6 +79,15 @@ type SessionIndex struct {
 	Version      int            `json:"version"`
 	Entries      []SessionEntry `json:"entries"`
 	OriginalPath string         `json:"originalPath"`
+	// Synthesized is set by ccs when no sessions-index.json exists and every
+	// entry was built from the transcripts
+	Synthesized bool `json:"-"`
+	// OtherPaths are session working directories that lost the vote for
+	// OriginalPath, most common first
+	OtherPaths []string `json:"-"`
+	// Err is set when a sessions-index.json exists but can't be read; its
+	// sessions are then synthesized from the transcripts
+	Err error `json:"-"`
 }
 
 type SessionEntry struct {
@@ -73,6 +111,17 @@ type Project struct {
 	MessageCount int
 	LastActive   time.Time
 	HasIndex     bool
+	IndexErr     error    // unreadable sessions-index.json, see SessionIndex.Err
+	OtherPaths   []string // other session working directories seen
+	MovedTo      string   // newer project at the same name, if Path is gone
+	Members      []ProjectMember // projects merged into this one by grouping
+	Usage        *ProjectUsage // from the stats cache, nil if not computed
+}
+
+// ProjectMember identifies a project merged into a grouped Project
+type ProjectMember struct {
+	Path    string
+	DirName string
 }
 
 // HistoryEntry represents a line in history.jsonl
@@ -122,9 +171,12 @@ type RawEntry struct {
 	GitBranch   string          `json:"gitBranch,omitempty"`
 	IsSidechain bool            `json:"isSidechain"`
 	CWD         string          `json:"cwd,omitempty"`
+	UUID        string          `json:"uuid,omitempty"`
+	RequestID   string          `json:"requestId,omitempty"`
 }
 
 type MessageContent struct {
+	ID      string          `json:"id,omitempty"`
 	Role    string          `json:"role"`
 	Content json.RawMessage `json:"content"`
 	Usage   *Usage          `json:"usage,omitempty"`
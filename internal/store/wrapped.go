@@ -0,0 +1,148 @@
+package store
+
+import (
+	"time"
+)
+
+// Wrapped is the year-in-review of all activity in one calendar year
+type Wrapped struct {
+	Year       int
+	Sessions   int // sessions started in the year
+	Messages   int
+	ToolCalls  int
+	ActiveDays int
+	Models     map[string]ModelUsage
+
+	Daily    map[string]int // YYYY-MM-DD -> messages
+	Projects map[string]int // project path -> messages
+	Tools    map[string]int // tool name -> calls
+	Files    map[string]int // edited file path -> edits
+	Hours    [24]int        // messages by local hour
+
+	Longest     *LongestSession
+	LongestPath string // project of the longest session
+	StreakDays  int
+	StreakStart string
+	StreakEnd   string
+}
+
+// Busiest returns the key with the most messages when daily counts are
+// grouped by key(date), e.g. by week or month
+func (w *Wrapped) Busiest(key func(date time.Time) string) (string, int) {
+	sums := make(map[string]int)
+	for date, n := range w.Daily {
+		if d, err := time.Parse("2006-01-02", date); err == nil {
+			sums[key(d)] += n
+		}
+	}
+	best, bestN := "", 0
+	for k, n := range sums {
+		if n > bestN || (n == bestN && k < best) {
+			best, bestN = k, n
+		}
+	}
+	return best, bestN
+}
+
+// ComputeWrapped scans the full history and aggregates the given year in
+// loc. Messages, tokens and hours count by their own timestamp; tools,
+// edited files and session counts belong to the year the session started.
+func ComputeWrapped(year int, loc *time.Location) (*Wrapped, error) {
+	projects, err := listProjectFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Wrapped{
+		Year:     year,
+		Models:   make(map[string]ModelUsage),
+		Daily:    make(map[string]int),
+		Projects: make(map[string]int),
+		Tools:    make(map[string]int),
+		Files:    make(map[string]int),
+	}
+	inYear := func(t time.Time) bool { return t.In(loc).Year() == year }
+
+	// Excluded projects count towards the totals but are never named, so
+	// they have no entry in fileProject
+	var files []string
+	fileProject := make(map[string]string)
+	for _, pf := range projects {
+		name := ProjectName(pf.Path, pf.DirName)
+		for _, f := range pf.Files {
+			files = append(files, f)
+			if !pf.Excluded {
+				fileProject[f] = name
+			}
+		}
+	}
+	sortByMtime(files)
+
+	dedup := NewDeduper()
+	var longestActive time.Duration
+	for _, path := range files {
+		ss, err := ScanSessionStats(path, dedup)
+		if err != nil {
+			continue
+		}
+
+		for _, ts := range ss.Timestamps {
+			if !inYear(ts) {
+				continue
+			}
+			local := ts.In(loc)
+			w.Messages++
+			w.Daily[local.Format("2006-01-02")]++
+			if name, ok := fileProject[path]; ok {
+				w.Projects[name]++
+			}
+			w.Hours[local.Hour()]++
+		}
+		for _, ev := range ss.Usage {
+			if !inYear(ev.Timestamp) {
+				continue
+			}
+			mu := w.Models[ev.Model]
+			mu.InputTokens += ev.Usage.InputTokens
+			mu.OutputTokens += ev.Usage.OutputTokens
+			mu.CacheReadInputTokens += ev.Usage.CacheReadInputTokens
+			mu.CacheCreationInputTokens += ev.Usage.CacheCreationInputTokens
+			w.Models[ev.Model] = mu
+		}
+
+		if ss.StartedAt.IsZero() || !inYear(ss.StartedAt) {
+			continue
+		}
+		w.Sessions++
+		w.ToolCalls += ss.ToolCalls
+		for name, n := range ss.Tools {
+			w.Tools[name] += n
+		}
+		if _, ok := fileProject[path]; !ok {
+			continue
+		}
+		for f, n := range ss.EditedFiles {
+			w.Files[f] += n
+		}
+		if active := ss.ActiveDuration(); active > longestActive {
+			longestActive = active
+			w.Longest = &LongestSession{
+				SessionID:      ss.SessionID,
+				Duration:       ss.WallDuration().Milliseconds(),
+				ActiveDuration: active.Milliseconds(),
+				MessageCount:   ss.UserMessages + ss.AsstMessages,
+				Timestamp:      ss.StartedAt.Format(time.RFC3339),
+			}
+			w.LongestPath = fileProject[path]
+		}
+	}
+
+	w.ActiveDays = len(w.Daily)
+	daily := make(DailySeries, len(w.Daily))
+	for date, n := range w.Daily {
+		daily[date] = float64(n)
+	}
+	w.StreakDays, w.StreakStart, w.StreakEnd = daily.LongestStreak()
+
+	return w, nil
+}